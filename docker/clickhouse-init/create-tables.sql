@@ -9,7 +9,54 @@ CREATE TABLE IF NOT EXISTS user_events.user_events_raw (
     event_type String,
     event_ts UInt64,
     state String,
+    page String,
+    device String,
+    referrer String,
+    product_id String,
+    vendor String,
+    price UInt32,
+    currency String, -- product.currency: price 의 통화 (주문 통화가 아님)
+    cart_lines UInt16,
+    cart_quantity UInt32,
+    home_country String,
+    loyalty_tier String,
+    session_number UInt32,
+    end_reason String,
+    session_duration_ms UInt64,
+    session_event_count UInt32,
     payload String
 ) ENGINE = MergeTree()
 ORDER BY (session_id, event_ts);
 
+
+-- 기존 테이블 마이그레이션
+-- CREATE TABLE IF NOT EXISTS 는 이미 있는 테이블의 컬럼을 바꾸지 않으므로, 이전 버전으로 만든 테이블에는 새 컬럼을 ALTER 로 추가합니다.
+-- (이 스크립트를 기존 볼륨에 다시 실행해도 안전하도록 모두 IF NOT EXISTS)
+
+-- 타입이 있는 이벤트 속성 (페이지 / 기기 / 유입 경로 / 상품)
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS page String AFTER state;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS device String AFTER page;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS referrer String AFTER device;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS product_id String AFTER referrer;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS vendor String AFTER product_id;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS price UInt32 AFTER vendor;
+
+-- 판매처 가격의 통화 (스키마 v2)
+-- purchased 의 주문 합계는 payload 의 extra.total_amount / extra.currency 입니다. 환율 변환을 하지 않으므로
+-- 통화가 섞인 장바구니 주문은 extra.currency = 'MIXED' 이고 total_amount 없이 extra.total_by_currency 에 통화별 합계가 있습니다.
+-- 매출 집계는 currency 별로 나누거나 MIXED 주문을 total_by_currency 로 풀어서 계산해야 합니다.
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS currency String AFTER price;
+
+-- 장바구니 / 주문 상품 목록 요약
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS cart_lines UInt16 AFTER currency;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS cart_quantity UInt32 AFTER cart_lines;
+
+-- 유저 프로필 (재방문 / 코호트 분석)
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS home_country String AFTER cart_quantity;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS loyalty_tier String AFTER home_country;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS session_number UInt32 AFTER loyalty_tier;
+
+-- 세션 수명 주기 (session_start / session_end 에만 채워짐)
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS end_reason String AFTER session_number;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS session_duration_ms UInt64 AFTER end_reason;
+ALTER TABLE user_events.user_events_raw ADD COLUMN IF NOT EXISTS session_event_count UInt32 AFTER session_duration_ms;
@@ -2,22 +2,51 @@ package main
 
 import (
 	"context"
+	"event-generator/internal/admin"
+	"event-generator/internal/checkpoint"
+	"event-generator/internal/clock"
+	"event-generator/internal/config"
 	"event-generator/internal/controller"
-	"event-generator/internal/event"
 	"event-generator/internal/fsm"
 	"event-generator/internal/generator"
+	"event-generator/internal/logging"
 	"event-generator/internal/metrics"
+	"event-generator/internal/queue"
+	"event-generator/internal/rng"
+	"event-generator/internal/serializer"
+	"event-generator/internal/sink"
 	"event-generator/internal/user"
 	"event-generator/internal/worker"
 	"fmt"
+	"math/rand/v2"
+	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
+	"sync"
 	"syscall"
 	"time"
 )
 
+// 종료 기한이 지난 뒤에도 Sink 를 닫을 때 (버퍼 Flush / Kafka 전송 확정) 기다리는 최소 시간
+const sinkCloseGrace = 5 * time.Second
+
+// backfill 진행 상황 출력 간격 (실제 시간)
+const backfillReportInterval = 10 * time.Second
+
 func main() {
+	// 0. 설정 로드 (기본값 < 설정 파일 < 환경 변수 < 플래그)
+	cfg, err := config.Parse(os.Args[0], os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+		os.Exit(2)
+	}
+
+	// stdout Sink 는 표준출력을 NDJSON 이벤트 스트림으로 쓰므로, 진단 로그는 처음부터 stderr 로 보냅니다.
+	if cfg.Sink.Type == sink.TypeStdout {
+		logging.SetOutput(os.Stderr)
+	}
+
 	// 1. 모든 코어 활용 설정
 	runtime.GOMAXPROCS(runtime.NumCPU())
 	ctx, cancel := context.WithCancel(context.Background())
@@ -28,20 +57,146 @@ func main() {
 	metricStore := metrics.NewInMemory()
 
 	// ======================
-	// Event Channel (버퍼 크기를 10만으로 유지)
+	// Serializer (json / avro / protobuf)
+	// ======================
+	var registry *serializer.FileRegistry
+	if cfg.Serializer.Registry != "" {
+		if registry, err = serializer.OpenRegistry(cfg.Serializer.Registry); err == nil && cfg.Serializer.Compatibility != "" {
+			err = registry.SetCompatibility("", cfg.Serializer.Compatibility)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+			os.Exit(1)
+		}
+	}
+	ser, err := serializer.New(cfg.Serializer.Format, registry, cfg.Kafka.Topic+"-value")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+		os.Exit(1)
+	}
+
+	// ======================
+	// Sink (kafka / file / stdout)
 	// ======================
-	eventCh := make(chan *event.Event, 100000)
+	out, err := sink.New(sink.Options{
+		Type:       cfg.Sink.Type,
+		Brokers:    cfg.Kafka.Brokers,
+		Topic:      cfg.Kafka.Topic,
+		Serializer: ser,
+		Path:       cfg.Sink.Path,
+		Stdout:     os.Stdout,
+		Metrics:    metricStore,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+		os.Exit(1)
+	}
+	// 장애 주입 (비활성화 상태에서는 그대로 전달하며, admin API 로 실행 중에 켤 수 있음)
+	faults := sink.NewFaultSink(out, cfg.Sink.Faults)
+	out = faults
+	if cfg.Sink.Faults.Enabled {
+		logging.Printf("[MAIN] sink fault injection enabled (%+v)\n", cfg.Sink.Faults)
+	}
+	cfg.Print(logging.Writer())
+	for _, w := range cfg.Warnings() {
+		logging.Printf("[CONFIG] warning: %s\n", w)
+	}
+
+	// ======================
+	// Event Channel (가득 차면 backpressure 정책에 따라 대기 / 유실 / 디스크 적재)
+	// ======================
+	eventQueue, err := queue.New(queue.Options{
+		Capacity:  cfg.Channel.Buffer,
+		Policy:    cfg.Channel.Policy,
+		WarnRatio: cfg.Channel.WarnRatio,
+		SpillPath: cfg.Channel.SpillPath,
+		SpillMax:  cfg.Channel.SpillMax,
+		Metrics:   metricStore,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+		os.Exit(1)
+	}
+
+	// ======================
+	// Clock & Random (seed / backfill 모드에서는 가상 시계, seed 모드에서는 시드 기반 난수 스트림)
+	// ======================
+	rngs := rng.NewFactory(cfg.Run.Seed)
+	var (
+		clk      clock.Clock = clock.Real{}
+		vclk     *clock.Virtual
+		poolRand *rand.PCG
+	)
+	if cfg.VirtualClock() {
+		vclk = clock.NewVirtual(cfg.Run.Start.Std())
+		clk = vclk
+	}
+	if cfg.Deterministic() {
+		poolRand = rngs.Source("user_pool")
+		logging.Printf("[MAIN] deterministic run (seed=%d, start=%s)\n", cfg.Run.Seed, cfg.Run.Start)
+	}
+	if cfg.Backfill() {
+		span := cfg.Run.End.Std().Sub(cfg.Run.Start.Std())
+		logging.Printf("[MAIN] backfill %s ~ %s (%s simulated, about %.0f events at the base target TPS before the load shape)\n",
+			cfg.Run.Start, cfg.Run.End, span, cfg.Load.TargetTPS*span.Seconds())
+	}
 
 	// ======================
 	// Core Components
 	// ======================
-	userPool := user.NewUserPool()
-	userPool.EnsureUsers(100000)
+	// 상품 카탈로그 (파일이 없으면 기본 카탈로그)
+	var catalog *generator.Catalog
+	if cfg.Catalog.Path != "" {
+		c, err := generator.LoadCatalog(cfg.Catalog.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+			os.Exit(2)
+		}
+		catalog = c
+		logging.Printf("[MAIN] loaded catalog %s (%d products, %d countries, %d keywords)\n",
+			cfg.Catalog.Path, len(c.Products), len(c.Countries), len(c.Keywords))
+	}
+	if catalog == nil {
+		catalog = generator.DefaultCatalog()
+	}
+
+	// 유저 프로필 (저장 파일이 있으면 불러오고 부족한 유저만 새로 생성)
+	// 체크포인트에서 재개하면 유저도 체크포인트에서 불러오므로 아래 Resume 뒤에 확보
+	resume := cfg.Checkpoint.Resume
+	userPool := user.NewUserPool(poolRand, cfg.Users.Distribution, catalog.CountryNames(), cfg.Users.Personas, clk)
+	if cfg.Users.Profiles != "" && !resume {
+		n, err := userPool.Load(cfg.Users.Profiles)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+			os.Exit(2)
+		}
+		logging.Printf("[MAIN] loaded %d user profiles from %s\n", n, cfg.Users.Profiles)
+	}
+	if !resume {
+		userPool.EnsureUsers(cfg.Users.Initial)
+	}
+
+	// 상태 전이 모델 (파일이 없으면 기본 그래프)
+	var model *fsm.Model
+	if cfg.FSM.Model != "" {
+		m, warnings, err := fsm.LoadModel(cfg.FSM.Model)
+		for _, w := range warnings {
+			logging.Printf("[MAIN] fsm model warning: %s\n", w)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+			os.Exit(2)
+		}
+		model = m
+		logging.Printf("[MAIN] loaded fsm model %s (%d states)\n", cfg.FSM.Model, len(model.States))
+	}
+	for _, w := range user.CheckPersonas(cfg.Users.Personas, model) {
+		logging.Printf("[MAIN] persona warning: %s\n", w)
+	}
 
-	// [수정] 이제 main에서 전역 rand를 직접 시딩하거나 전달할 필요가 없습니다.
-	// fsm과 generator 모두 내부적으로 math/rand/v2의 전역 소스를 사용합니다.
-	fsmEngine := fsm.NewSimpleFSM()               // 인자 제거
-	payloadGen := generator.NewPayloadGenerator() // 인자 없음 확인
+	// fsm과 generator는 세션별 난수 스트림(Session.Rand)을 사용합니다.
+	fsmEngine := fsm.NewSimpleFSM(model)
+	payloadGen := generator.NewPayloadGenerator(catalog)
 
 	// ======================
 	// Session Manager
@@ -50,90 +205,334 @@ func main() {
 		userPool,
 		fsmEngine,
 		payloadGen,
-		eventCh,
+		eventQueue,
 		metricStore,
-		30*time.Minute,
+		cfg.Session.TTL.Std(),
+		cfg.Session.Shards,
+		clk,
+		rngs,
 	)
 
+	// 저장된 세션이 있으면 이어서 진행
+	var sessionStore *user.BoltStore
+	if cfg.Session.Store != "" {
+		if resume {
+			logging.Printf("[MAIN] resuming from a checkpoint - sessions in %s are not restored (saved again on shutdown)\n", cfg.Session.Store)
+		} else if cfg.Users.Profiles == "" {
+			logging.Println("[MAIN] session.store is set without users.profiles - restored sessions are attached to newly generated user profiles")
+		}
+		store, err := user.OpenBoltStore(cfg.Session.Store)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+			os.Exit(2)
+		}
+		sessionStore = store
+		if !resume {
+			restored, skipped, err := sm.Restore(store)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+				os.Exit(2)
+			}
+			logging.Printf("[MAIN] restored %d sessions from %s (skipped %d)\n", restored, cfg.Session.Store, skipped)
+		}
+	}
+
 	// ======================
 	// Load Controller
 	// ======================
-	targetTPS := 20000
 	loadController := controller.NewLoadController(
-		targetTPS,
+		cfg.Load.TargetTPS,
+		cfg.Load.Goroutines,
+		cfg.Load.TickInterval.Std(),
 		userPool,
 		sm,
+		clk,
 	)
-	go loadController.Start()
+	loadController.SetMaxEvents(cfg.Run.Events)
+	if cfg.Backfill() {
+		loadController.SetEnd(cfg.Run.End.Std())
+	}
+	// 설정은 Parse 에서 검증했으므로 오류가 없습니다.
+	if shape, _ := cfg.Load.Shape.Build(); shape != nil {
+		loadController.SetShape(shape)
+		logging.Printf("[MAIN] load shape enabled (timezone=%s)\n", shape.Location)
+	}
+
+	// ======================
+	// Checkpoint (유저 풀 / 진행 중인 세션 / 난수 스트림 / 시계 / 카운터를 주기적으로 저장하고 재시작 시 이어서 생성)
+	// ======================
+	var ckpt *checkpoint.Checkpointer
+	if cfg.Checkpoint.Dir != "" {
+		ckpt, err = checkpoint.New(cfg.Checkpoint.Dir, cfg.Checkpoint.Keep, cfg.Run.Seed, clk, userPool, sm, loadController)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[MAIN] %v\n", err)
+			os.Exit(2)
+		}
+		if resume {
+			meta, err := ckpt.Resume()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[MAIN] resume: %v\n", err)
+				os.Exit(2)
+			}
+			userPool.EnsureUsers(cfg.Users.Initial)
+			logging.Printf("[MAIN] resumed from checkpoint %d in %s (clock=%s, users=%d, sessions=%d, generated=%d)\n",
+				meta.Seq, cfg.Checkpoint.Dir, meta.Clock.Format(time.RFC3339Nano), meta.Users, meta.Sessions, meta.Manager.Generated)
+		}
+		loadController.SetCheckpoint(cfg.Checkpoint.Interval.Std(), func() { saveCheckpoint(ckpt) })
+	}
+	// 이번 실행에서 생성한 이벤트 수 (체크포인트에서 재개하면 이전 실행의 생성 수를 빼고 대조)
+	resumedGenerated := sm.Generated()
+
+	if vclk != nil {
+		go loadController.RunSequential(vclk)
+	} else {
+		go loadController.Start()
+	}
+	if cfg.Backfill() {
+		go reportBackfill(ctx, vclk, cfg.Run.Start.Std(), cfg.Run.End.Std(), sm)
+	}
 
 	// ======================
-	// Workers (Kafka Producer)
+	// Workers
 	// ======================
 	// [성능 팁] TPS 2만 이상에서는 워커 수를 CPU 코어 수(runtime.NumCPU()) 정도로 늘리는 것이 유리합니다.
-	workerCount := 12 // runtime.NumCPU()
-	fmt.Printf("[MAIN] Using %d workers (CPU=%d)\n", workerCount, runtime.NumCPU())
+	workerCount := cfg.Worker.Count
+	logging.Printf("[MAIN] Using %d workers (CPU=%d)\n", workerCount, runtime.NumCPU())
 
+	// workerCtx 는 종료 기한이 지났을 때만 취소합니다. (평소에는 채널이 닫힐 때까지 남은 이벤트를 모두 전송)
+	workerCtx, workerCancel := context.WithCancel(context.Background())
+	defer workerCancel()
+	var workers sync.WaitGroup
 	for i := 0; i < workerCount; i++ {
 		w := worker.NewWorker(
 			i,
-			eventCh,
+			eventQueue.C(),
 			metricStore,
-			"localhost:9092",
-			"user_events",
+			out,
 		)
-		go w.Run(ctx)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			w.Run(workerCtx)
+		}()
 	}
 
 	// ======================
 	// Metrics Snapshot & Channel Lag Monitor
 	// ======================
+	tpsMeter := metrics.NewRateMeter()
 	go func() {
-		ticker := time.NewTicker(1 * time.Second)
+		ticker := time.NewTicker(cfg.Metrics.Interval.Std())
 		defer ticker.Stop()
 
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case <-ticker.C:
+			case now := <-ticker.C:
 				snapshot := metricStore.Snapshot()
-				fmt.Printf("[METRICS] %v | Lag: %d/%d\n",
-					snapshot, len(eventCh), cap(eventCh))
+				tpsMeter.Observe(snapshot.TotalEvents, now)
+				logging.Printf("[METRICS] %v | Lag: %d/%d (spilled %d) | TPS target/achieved: %.1f/%.1f\n",
+					snapshot, eventQueue.Len(), eventQueue.Cap(), eventQueue.Spilled(), loadController.CurrentTarget(), loadController.AchievedTPS())
 			}
 		}
 	}()
 
+	// ======================
+	// Prometheus /metrics
+	// ======================
+	if cfg.Metrics.Listen != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.NewPrometheusHandler(metricStore,
+			metrics.GaugeFunc{Name: "eventgen_active_sessions", Help: "Sessions currently held by the SessionManager.",
+				Fn: func() float64 { return float64(sm.ActiveSessions()) }},
+			metrics.GaugeFunc{Name: "eventgen_schedule_lag_seconds", Help: "How far the most overdue scheduled session is behind its next-action time.",
+				Fn: func() float64 { return sm.ScheduleLag().Seconds() }},
+			metrics.GaugeFunc{Name: "eventgen_event_channel_depth", Help: "Events waiting in the SessionManager to Worker channel.",
+				Fn: func() float64 { return float64(eventQueue.Len()) }},
+			metrics.GaugeFunc{Name: "eventgen_event_channel_capacity", Help: "Capacity of the event channel.",
+				Fn: func() float64 { return float64(eventQueue.Cap()) }},
+			metrics.GaugeFunc{Name: "eventgen_spill_queue_depth", Help: "Events waiting in the local disk queue (spill policy).",
+				Fn: func() float64 { return float64(eventQueue.Spilled()) }},
+			metrics.GaugeFunc{Name: "eventgen_target_tps", Help: "Target events per second after the load shape is applied.",
+				Fn: loadController.CurrentTarget},
+			metrics.GaugeFunc{Name: "eventgen_generated_tps", Help: "Events per second produced by the SessionManager, measured by the rate controller every second.",
+				Fn: loadController.AchievedTPS},
+			metrics.GaugeFunc{Name: "eventgen_actual_tps", Help: "Events per second written to the sink, sampled every metrics interval.",
+				Fn: tpsMeter.Rate},
+		))
+		go func() {
+			logging.Printf("[MAIN] serving Prometheus metrics on %s/metrics\n", cfg.Metrics.Listen)
+			if err := http.ListenAndServe(cfg.Metrics.Listen, mux); err != nil {
+				logging.Printf("[MAIN] metrics server error: %v\n", err)
+			}
+		}()
+	}
+
+	// ======================
+	// Admin API (실행 중 TPS / 일시 정지 / 유저 수 / 장애 주입 / FSM 모델 변경)
+	// ======================
+	if cfg.Admin.Listen != "" {
+		adminServer := admin.NewServer(loadController, sm, userPool, fsmEngine, faults, cfg.FSM.Model, cfg.Users.Personas)
+		go func() {
+			logging.Printf("[MAIN] serving admin API on %s\n", cfg.Admin.Listen)
+			if err := http.ListenAndServe(cfg.Admin.Listen, adminServer.Handler()); err != nil {
+				logging.Printf("[MAIN] admin server error: %v\n", err)
+			}
+		}()
+	}
+
 	// ======================
 	// Graceful Shutdown
 	// ======================
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
-	<-sig
+	select {
+	case <-sig:
+	case <-loadController.Done():
+		logging.Printf("[MAIN] generated %d events\n", sm.Generated())
+	}
 
-	fmt.Println("\n[MAIN] shutting down...")
+	logging.Printf("\n[MAIN] shutting down (timeout %s)...\n", cfg.Shutdown.Timeout)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout.Std())
+	defer shutdownCancel()
+	// 종료 중에 신호를 한 번 더 받으면 기다리지 않고 강제 종료
+	go func() {
+		select {
+		case <-sig:
+			logging.Println("[MAIN] second signal - forcing shutdown")
+			shutdownCancel()
+		case <-shutdownCtx.Done():
+		}
+	}()
 
-	// 1. 이벤트 생성 중단
-	loadController.Stop()
+	// 1. 이벤트 생성 중단 (진행 중인 Step 까지 기다림)
+	if err := loadController.Stop(shutdownCtx); err != nil {
+		logging.Printf("[MAIN] %v\n", err)
+	}
+	if ckpt != nil {
+		saveCheckpoint(ckpt)
+	}
+	if sessionStore != nil {
+		if n, err := sm.Save(sessionStore); err != nil {
+			logging.Printf("[MAIN] %v\n", err)
+		} else {
+			logging.Printf("[MAIN] saved %d sessions to %s\n", n, cfg.Session.Store)
+		}
+		if err := sessionStore.Close(); err != nil {
+			logging.Printf("[MAIN] %v\n", err)
+		}
+	}
 
-	// 2. 채널에 남은 이벤트 소비 대기
-	fmt.Println("[MAIN] Draining event channel...")
-	drainCtx, drainCancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer drainCancel()
+	// 2. 큐 닫기 (디스크 큐를 채널로 모두 되돌린 뒤 채널을 닫음)
+	logging.Printf("[MAIN] draining %d queued events...\n", eventQueue.Pending())
+	spillLeft, err := eventQueue.Close(shutdownCtx)
+	if err != nil {
+		logging.Printf("[MAIN] %v\n", err)
+	}
 
-	for len(eventCh) > 0 {
+	// 3. 워커가 채널을 비우고 Flush 할 때까지 대기 (기한이 지나면 강제 중단)
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		logging.Println("[MAIN] drain deadline exceeded - stopping workers")
+		workerCancel()
+		<-workersDone
+	}
+
+	// 4. Sink 종료 (Kafka 는 남은 배치 전송 및 전송 확정까지 대기)
+	// 기한이 이미 지났더라도 워커가 넘긴 이벤트를 버퍼에서 내보낼 수 있도록 최소 sinkCloseGrace 는 기다림
+	closeWait := sinkCloseGrace
+	if deadline, ok := shutdownCtx.Deadline(); ok && shutdownCtx.Err() == nil {
+		closeWait = max(time.Until(deadline), sinkCloseGrace)
+	}
+	closeDone := make(chan error, 1)
+	go func() { closeDone <- out.Close() }()
+	select {
+	case err := <-closeDone:
+		if err != nil {
+			logging.Printf("[MAIN] sink close error: %v\n", err)
+		}
+	case <-time.After(closeWait):
+		logging.Println("[MAIN] sink close deadline exceeded - unacknowledged events are counted as lost")
+	}
+	cancel()
+
+	// 5. 생성 / 전송 / 유실 대조
+	printReconciliation(metricStore.Snapshot(), sm.Generated()-resumedGenerated, int64(eventQueue.Len()), spillLeft, faults.Config())
+
+	if cfg.Users.Profiles != "" {
+		if err := userPool.Save(cfg.Users.Profiles); err != nil {
+			logging.Printf("[MAIN] %v\n", err)
+		} else {
+			logging.Printf("[MAIN] saved %d user profiles to %s\n", userPool.TotalCount(), cfg.Users.Profiles)
+		}
+	}
+	logging.Println("[MAIN] shutdown complete")
+}
+
+// reportBackfill : backfill 진행 상황 (가상 시각 / 진행률 / 실제 시간 대비 배속 / 남은 시간) 을 주기적으로 출력합니다.
+func reportBackfill(ctx context.Context, vclk *clock.Virtual, start, end time.Time, sm *user.SessionManager) {
+	ticker := time.NewTicker(backfillReportInterval)
+	defer ticker.Stop()
+
+	lastWall, lastSim, lastGenerated := time.Now(), vclk.Now(), sm.Generated()
+	for {
 		select {
-		case <-drainCtx.Done():
-			fmt.Println("[MAIN] Drain timeout - some events might be lost")
-			goto ForceStop
-		default:
-			time.Sleep(100 * time.Millisecond)
+		case <-ctx.Done():
+			return
+		case wall := <-ticker.C:
+			sim, generated := vclk.Now(), sm.Generated()
+			speed := float64(sim.Sub(lastSim)) / float64(wall.Sub(lastWall))
+			eta := "-"
+			if speed > 0 {
+				eta = time.Duration(float64(end.Sub(sim)) / speed).Round(time.Second).String()
+			}
+			logging.Printf("[BACKFILL] simulated %s (%.1f%%) | generated=%d (%.0f/s) | %.0fx real time | ETA %s\n",
+				sim.Format(time.RFC3339), 100*float64(sim.Sub(start))/float64(end.Sub(start)),
+				generated, float64(generated-lastGenerated)/wall.Sub(lastWall).Seconds(), speed, eta)
+			lastWall, lastSim, lastGenerated = wall, sim, generated
 		}
 	}
+}
 
-ForceStop:
-	// 3. 워커 종료 및 전송 플러시
-	cancel()
+// saveCheckpoint : 체크포인트를 저장하고 결과를 출력합니다. (실패해도 생성은 계속)
+func saveCheckpoint(ckpt *checkpoint.Checkpointer) {
+	start := time.Now()
+	meta, err := ckpt.Save()
+	if err != nil {
+		logging.Printf("[CHECKPOINT] %v\n", err)
+		return
+	}
+	logging.Printf("[CHECKPOINT] saved checkpoint %d to %s in %s (users=%d, sessions=%d, generated=%d)\n",
+		meta.Seq, ckpt.Dir(), time.Since(start).Round(time.Millisecond), meta.Users, meta.Sessions, meta.Manager.Generated)
+}
+
+// printReconciliation : 생성한 이벤트가 모두 전송 / 실패 / 유실 중 하나로 집계되는지 대조합니다.
+// undelivered 는 기한 안에 보내지 못하고 채널과 디스크 큐에 남은 이벤트,
+// unaccounted 는 어디에도 집계되지 않은 나머지 (Sink 종료 기한 초과로 확정을 받지 못한 Kafka 메시지 등) 입니다.
+func printReconciliation(snap metrics.Snapshot, generated, inChannel, inSpill int64, faults sink.FaultConfig) {
+	var dropped int64
+	for _, n := range snap.DroppedByPolicy {
+		dropped += n
+	}
+	undelivered := inChannel + inSpill
+	unaccounted := generated - snap.Delivered - snap.DeliveryFailed - dropped - undelivered
+	lost := generated - snap.Delivered
 
-	time.Sleep(2 * time.Second)
-	fmt.Println("[MAIN] shutdown complete")
+	logging.Printf("[MAIN] reconciliation: generated=%d delivered=%d failed=%d dropped=%d undelivered=%d (channel %d, spill %d) unaccounted=%d\n",
+		generated, snap.Delivered, snap.DeliveryFailed, dropped, undelivered, inChannel, inSpill, unaccounted)
+	if faults.Enabled {
+		logging.Println("[MAIN] note: sink fault injection is enabled (injected drops count as delivered, duplicates as extra deliveries)")
+	}
+	if lost == 0 {
+		logging.Println("[MAIN] lossless shutdown: every generated event was delivered")
+	} else {
+		logging.Printf("[MAIN] %d of %d generated events were not delivered\n", lost, generated)
+	}
 }
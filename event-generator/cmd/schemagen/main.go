@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"event-generator/internal/serializer"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// schemagen : event.Event 구조체에서 Avro/Protobuf 스키마 파일을 생성하거나, 커밋된 파일과 어긋났는지 검사합니다.
+//
+//	go run ./cmd/schemagen -write   # schemas/ 갱신
+//	go run ./cmd/schemagen -check   # 구조체와 schemas/ 가 다르면 exit 1 (CI 용)
+//	go run ./cmd/schemagen -check -registry schemas/registry.json -subject user_events-value -format avro
+//	                                # 레지스트리 최신 버전 대비 호환성까지 검사
+func main() {
+	dir := flag.String("dir", "schemas", "directory holding event.avsc and event.proto")
+	write := flag.Bool("write", false, "regenerate the schema files")
+	check := flag.Bool("check", false, "fail if the schema files differ from the Go struct")
+	registryPath := flag.String("registry", "", "file-based schema registry to register the schema in")
+	subject := flag.String("subject", "user_events-value", "registry subject")
+	format := flag.String("format", serializer.FormatAvro, "schema registered in the registry: avro or protobuf")
+	flag.Parse()
+
+	if !*write && !*check && *registryPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	avsc, err := serializer.AvroSchema()
+	if err != nil {
+		fail(err)
+	}
+	proto, err := serializer.ProtoSchema()
+	if err != nil {
+		fail(err)
+	}
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"event.avsc", append(avsc, '\n')},
+		{"event.proto", proto},
+	}
+
+	if *write {
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			fail(err)
+		}
+		for _, f := range files {
+			path := filepath.Join(*dir, f.name)
+			if err := os.WriteFile(path, f.data, 0o644); err != nil {
+				fail(err)
+			}
+			fmt.Printf("[SCHEMAGEN] wrote %s\n", path)
+		}
+	}
+
+	if *check {
+		stale := false
+		for _, f := range files {
+			path := filepath.Join(*dir, f.name)
+			cur, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(cur, f.data) {
+				fmt.Printf("[SCHEMAGEN] %s is out of date (run: go run ./cmd/schemagen -write)\n", path)
+				stale = true
+			}
+		}
+		if stale {
+			os.Exit(1)
+		}
+		fmt.Println("[SCHEMAGEN] schema files are up to date")
+	}
+
+	if *registryPath != "" {
+		reg, err := serializer.OpenRegistry(*registryPath)
+		if err != nil {
+			fail(err)
+		}
+		// serializer.New 가 등록과 호환성 검사를 함께 수행합니다.
+		if _, err := serializer.New(*format, reg, *subject); err != nil {
+			fail(err)
+		}
+		latest, _ := reg.Latest(*subject)
+		fmt.Printf("[SCHEMAGEN] %s registered as id=%d version=%d (%s)\n", *subject, latest.ID, latest.Version, latest.SchemaType)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "[SCHEMAGEN] %v\n", err)
+	os.Exit(1)
+}
@@ -4,9 +4,14 @@ go 1.23
 
 toolchain go1.24.11
 
-require github.com/segmentio/kafka-go v0.4.47
+require (
+	github.com/segmentio/kafka-go v0.4.47
+	go.etcd.io/bbolt v1.4.3
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/klauspost/compress v1.15.9 // indirect
 	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	golang.org/x/sys v0.29.0 // indirect
 )
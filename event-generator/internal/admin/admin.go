@@ -0,0 +1,315 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"event-generator/internal/config"
+	"event-generator/internal/controller"
+	"event-generator/internal/fsm"
+	"event-generator/internal/logging"
+	"event-generator/internal/sink"
+	"event-generator/internal/user"
+)
+
+// =======================
+// Admin API
+// =======================
+
+// Server 는 재시작 없이 생성기를 제어하는 HTTP API 입니다.
+// 모든 변경은 다음 tick / 다음 Step 부터 반영되며 진행 중인 세션은 그대로 유지됩니다.
+//
+//	GET  /status       현재 상태
+//	PUT  /tps          {"target_tps": 40000}
+//	POST /pause        생성 일시 정지
+//	POST /resume       생성 재개
+//	PUT  /users        {"size": 50000} (0 = 자동)
+//	GET  /faults       장애 주입 설정
+//	PUT  /faults       sink.FaultConfig
+//	POST /fsm/reload   {"path": "configs/fsm.default.yaml"} (생략하면 시작 시 모델 경로, 빈 경로면 기본 그래프)
+//	                   경로는 시작 시 모델 파일(-fsm.model)과 같은 디렉터리만 허용
+//	PUT  /shape        config.ShapeConfig (빈 객체면 shape 해제)
+type Server struct {
+	load     *controller.LoadController
+	sessions *user.SessionManager
+	users    *user.UserPool
+	engine   *fsm.SimpleFSM
+	faults   *sink.FaultSink
+
+	// FSM 다시 읽기 설정
+	reloadMu  sync.Mutex
+	modelPath string
+	modelDir  string // /fsm/reload 로 읽을 수 있는 디렉터리 (비어 있으면 기본 그래프로만 되돌릴 수 있음)
+	personas  []user.Persona
+}
+
+// NewServer
+// modelPath 는 /fsm/reload 에서 경로를 생략했을 때 다시 읽을 모델 파일이며 (비어 있으면 기본 그래프),
+// admin API 는 인증이 없으므로 /fsm/reload 는 modelPath 와 같은 디렉터리의 파일만 읽습니다.
+// personas 는 새 모델과 맞지 않는 전이 배수를 경고하는 데 사용합니다.
+func NewServer(
+	lc *controller.LoadController,
+	sm *user.SessionManager,
+	up *user.UserPool,
+	engine *fsm.SimpleFSM,
+	faults *sink.FaultSink,
+	modelPath string,
+	personas []user.Persona,
+) *Server {
+	var modelDir string
+	if modelPath != "" {
+		if abs, err := filepath.Abs(modelPath); err == nil {
+			modelDir = filepath.Dir(abs)
+		}
+	}
+	return &Server{
+		load:      lc,
+		sessions:  sm,
+		users:     up,
+		engine:    engine,
+		faults:    faults,
+		modelPath: modelPath,
+		modelDir:  modelDir,
+		personas:  personas,
+	}
+}
+
+// Handler : admin API 라우팅
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("PUT /tps", s.handleTPS)
+	mux.HandleFunc("POST /pause", s.handlePause)
+	mux.HandleFunc("POST /resume", s.handleResume)
+	mux.HandleFunc("PUT /users", s.handleUsers)
+	mux.HandleFunc("GET /faults", s.handleGetFaults)
+	mux.HandleFunc("PUT /faults", s.handlePutFaults)
+	mux.HandleFunc("POST /fsm/reload", s.handleReload)
+	mux.HandleFunc("PUT /shape", s.handleShape)
+	return mux
+}
+
+// Status : GET /status 응답
+type Status struct {
+	Paused         bool              `json:"paused"`
+	BaseTPS        float64           `json:"base_tps"`
+	TargetTPS      float64           `json:"target_tps"`
+	AchievedTPS    float64           `json:"achieved_tps"`
+	ShapeEnabled   bool              `json:"shape_enabled"`
+	Generated      int64             `json:"generated"`
+	ActiveSessions int               `json:"active_sessions"`
+	ScheduleLagSec float64           `json:"schedule_lag_sec"`
+	UsersTotal     int               `json:"users_total"`
+	UsersActive    int               `json:"users_active"`
+	UserPoolSize   int               `json:"user_pool_size"` // 0 = 자동
+	FSMStates      int               `json:"fsm_states"`
+	Faults         *sink.FaultConfig `json:"faults,omitempty"`
+}
+
+func (s *Server) status() Status {
+	st := Status{
+		Paused:         s.load.Paused(),
+		BaseTPS:        s.load.BaseTPS(),
+		TargetTPS:      s.load.CurrentTarget(),
+		AchievedTPS:    s.load.AchievedTPS(),
+		ShapeEnabled:   s.load.ShapeEnabled(),
+		Generated:      s.sessions.Generated(),
+		ActiveSessions: s.sessions.ActiveSessions(),
+		ScheduleLagSec: s.sessions.ScheduleLag().Seconds(),
+		UsersTotal:     s.users.TotalCount(),
+		UsersActive:    s.users.ActiveCount(),
+		UserPoolSize:   s.load.UserPoolSize(),
+		FSMStates:      len(s.engine.Model().States),
+	}
+	if s.faults != nil {
+		f := s.faults.Config()
+		st.Faults = &f
+	}
+	return st
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+func (s *Server) handleTPS(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TargetTPS *float64 `json:"target_tps"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.TargetTPS == nil || *req.TargetTPS <= 0 {
+		writeError(w, http.StatusBadRequest, errors.New("target_tps must be > 0"))
+		return
+	}
+	s.load.SetTargetTPS(*req.TargetTPS)
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	s.load.Pause()
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	s.load.Resume()
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Size *int `json:"size"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Size == nil || *req.Size < 0 {
+		writeError(w, http.StatusBadRequest, errors.New("size must be >= 0 (0 = automatic)"))
+		return
+	}
+	s.load.SetUserPoolSize(*req.Size)
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+func (s *Server) handleGetFaults(w http.ResponseWriter, r *http.Request) {
+	if s.faults == nil {
+		writeError(w, http.StatusNotFound, errors.New("fault injection is not available"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.faults.Config())
+}
+
+// handlePutFaults : 보낸 항목만 바꾸고 나머지는 현재 설정을 유지합니다.
+func (s *Server) handlePutFaults(w http.ResponseWriter, r *http.Request) {
+	if s.faults == nil {
+		writeError(w, http.StatusNotFound, errors.New("fault injection is not available"))
+		return
+	}
+	cfg := s.faults.Config()
+	if !readJSON(w, r, &cfg) {
+		return
+	}
+	if err := s.faults.SetConfig(cfg); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	logging.Printf("[ADMIN] faults -> %+v\n", cfg)
+	writeJSON(w, http.StatusOK, cfg)
+}
+
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path *string `json:"path"`
+	}
+	if r.ContentLength != 0 && !readJSON(w, r, &req) {
+		return
+	}
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	path := s.modelPath
+	if req.Path != nil {
+		path = *req.Path
+	}
+	if err := s.checkModelPath(path); err != nil {
+		logging.Printf("[ADMIN] fsm reload rejected (%q): %v\n", path, err)
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+	var (
+		model    *fsm.Model
+		warnings []string
+	)
+	if path != "" {
+		m, ws, err := fsm.LoadModel(path)
+		if err != nil {
+			// 파서 오류에는 파일 내용 일부가 들어갈 수 있으므로 자세한 내용은 로그에만 남김
+			logging.Printf("[ADMIN] fsm reload failed: %v\n", err)
+			writeError(w, http.StatusBadRequest, fmt.Errorf("failed to load fsm model %q (see generator log)", path))
+			return
+		}
+		model, warnings = m, ws
+	}
+	warnings = append(warnings, user.CheckPersonas(s.personas, model)...)
+
+	s.engine.SetModel(model)
+	s.modelPath = path
+	logging.Printf("[ADMIN] fsm model reloaded from %q (%d states)\n", path, len(s.engine.Model().States))
+	for _, warn := range warnings {
+		logging.Printf("[ADMIN] fsm model warning: %s\n", warn)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"path":     path,
+		"states":   len(s.engine.Model().States),
+		"warnings": warnings,
+	})
+}
+
+// checkModelPath : 빈 경로 (기본 그래프) 이거나 시작 시 모델 파일과 같은 디렉터리의 파일만 허용합니다.
+// 파일 시스템에 접근하지 않고 경로만으로 판단하므로 거부된 경로의 파일 존재 여부는 드러나지 않습니다.
+func (s *Server) checkModelPath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if s.modelDir == "" {
+		return errors.New("fsm reload from a file is disabled: the generator was started without -fsm.model")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil || filepath.Dir(abs) != s.modelDir {
+		return errors.New("fsm model path must be a file in the -fsm.model directory")
+	}
+	return nil
+}
+
+func (s *Server) handleShape(w http.ResponseWriter, r *http.Request) {
+	var cfg config.ShapeConfig
+	if !readJSON(w, r, &cfg) {
+		return
+	}
+	shape, err := cfg.Build()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.load.SetShape(shape)
+	if shape != nil {
+		logging.Printf("[ADMIN] load shape replaced (timezone=%s)\n", shape.Location)
+	} else {
+		logging.Println("[ADMIN] load shape disabled")
+	}
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+// =======================
+// JSON helpers
+// =======================
+
+// readJSON : 요청 본문을 v 에 디코딩합니다. 실패하면 400 을 응답하고 false 를 반환합니다.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
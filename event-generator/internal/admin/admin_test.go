@@ -0,0 +1,73 @@
+package admin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"event-generator/internal/admin"
+	"event-generator/internal/fsm"
+)
+
+// TestReloadPathRestricted : /fsm/reload 는 시작 시 모델 파일과 같은 디렉터리의 파일만 읽고,
+// 파서 오류의 자세한 내용 (파일 내용 일부) 은 응답에 싣지 않아야 합니다.
+func TestReloadPathRestricted(t *testing.T) {
+	model, err := os.ReadFile("../../configs/fsm.default.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	dir := filepath.Join(root, "models")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	const secret = "secret-token-1234"
+	files := map[string]string{
+		filepath.Join(dir, "fsm.yaml"):    string(model),
+		filepath.Join(dir, "alt.yaml"):    string(model),
+		filepath.Join(dir, "broken.yaml"): "states: " + secret + "\n",
+		filepath.Join(root, "other.yaml"): string(model),
+		filepath.Join(root, "leak.yaml"):  "states: " + secret + "\n",
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name      string
+		modelPath string
+		body      string
+		want      int
+	}{
+		{"omitted", filepath.Join(dir, "fsm.yaml"), "", http.StatusOK},
+		{"same dir", filepath.Join(dir, "fsm.yaml"), `{"path": "` + filepath.Join(dir, "alt.yaml") + `"}`, http.StatusOK},
+		{"default graph", filepath.Join(dir, "fsm.yaml"), `{"path": ""}`, http.StatusOK},
+		{"parse error", filepath.Join(dir, "fsm.yaml"), `{"path": "` + filepath.Join(dir, "broken.yaml") + `"}`, http.StatusBadRequest},
+		{"outside", filepath.Join(dir, "fsm.yaml"), `{"path": "` + filepath.Join(root, "leak.yaml") + `"}`, http.StatusForbidden},
+		{"dot dot", filepath.Join(dir, "fsm.yaml"), `{"path": "` + dir + `/../other.yaml"}`, http.StatusForbidden},
+		{"subdir", filepath.Join(dir, "fsm.yaml"), `{"path": "` + filepath.Join(dir, "x", "fsm.yaml") + `"}`, http.StatusForbidden},
+		{"system file", filepath.Join(dir, "fsm.yaml"), `{"path": "/etc/passwd"}`, http.StatusForbidden},
+		{"no model configured", "", `{"path": "` + filepath.Join(dir, "alt.yaml") + `"}`, http.StatusForbidden},
+		{"no model configured default", "", `{"path": ""}`, http.StatusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := admin.NewServer(nil, nil, nil, fsm.NewSimpleFSM(nil), nil, c.modelPath, nil)
+			req := httptest.NewRequest(http.MethodPost, "/fsm/reload", strings.NewReader(c.body))
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != c.want {
+				t.Fatalf("status %d, want %d: %s", rec.Code, c.want, rec.Body)
+			}
+			if strings.Contains(rec.Body.String(), secret) {
+				t.Fatalf("response leaks file contents: %s", rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"event-generator/internal/clock"
+	"event-generator/internal/controller"
+	"event-generator/internal/logging"
+	"event-generator/internal/user"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =======================
+// Layout
+// =======================
+
+// 체크포인트 디렉터리 구성
+//
+//	dir/
+//	  LATEST               마지막으로 완성된 체크포인트 이름
+//	  ckpt-00000001/
+//	    meta.json          시계 / 카운터 / 난수 스트림 상태 (Meta)
+//	    users.json         유저 프로필 (users.profiles 와 같은 형식)
+//	    sessions.db        진행 중인 세션 (bbolt SessionStore)
+//
+// 체크포인트는 임시 디렉터리에 모두 쓴 뒤 rename 하고 LATEST 를 바꾸므로, 저장 도중 종료되어도 이전 체크포인트로 재개할 수 있습니다.
+const (
+	latestFile   = "LATEST"
+	metaFile     = "meta.json"
+	usersFile    = "users.json"
+	sessionsFile = "sessions.db"
+	namePrefix   = "ckpt-"
+)
+
+// metaVersion : meta.json 형식 버전
+const metaVersion = 1
+
+// Meta : 체크포인트 시점의 생성기 상태 (유저 / 세션 제외)
+type Meta struct {
+	Version  int       `json:"version"`
+	Seq      int       `json:"seq"`
+	TakenAt  time.Time `json:"taken_at"` // 저장한 실제 시각
+	Seed     uint64    `json:"seed"`     // 0 이면 비결정 모드
+	Clock    time.Time `json:"clock"`    // 생성기 시계 (seed 모드에서는 가상 시계, 재개할 때 이 시각부터 이어서 흐름)
+	Users    int       `json:"users"`
+	Sessions int       `json:"sessions"`
+
+	UserRNG []byte               `json:"user_rng,omitempty"` // 유저 풀 난수 스트림 (seed 모드)
+	Manager user.ManagerState    `json:"manager"`
+	Load    controller.LoadState `json:"load"`
+}
+
+// =======================
+// Checkpointer
+// =======================
+
+// Checkpointer 는 유저 풀 / 진행 중인 세션 / 난수 스트림 / 시계 / 카운터를 dir 에 주기적으로 저장하고,
+// 재시작할 때 마지막 체크포인트부터 이어서 생성하도록 복원합니다.
+//
+// Save 는 생성이 멈춘 상태 (LoadController 의 체크포인트 콜백 안, 또는 Stop 이후) 에서 호출해야 전체가 한 시점의 상태가 됩니다.
+// seed 모드에서는 체크포인트에서 재개한 출력이 끊지 않고 실행한 출력과 바이트 단위로 이어지며,
+// 비결정 모드에서는 유저 ID / 세션 / 장바구니 / 카운터가 이어집니다.
+// 체크포인트 이후 ~ 종료 전에 생성된 이벤트는 재개할 때 다시 생성되므로 (seed 모드에서는 같은 event_id) 하류에서 중복을 제거해야 합니다.
+type Checkpointer struct {
+	dir  string
+	keep int // 보관할 체크포인트 수
+
+	seed     uint64
+	clock    clock.Clock
+	users    *user.UserPool
+	sessions *user.SessionManager
+	load     *controller.LoadController
+
+	mu  sync.Mutex
+	seq int // 마지막 체크포인트 번호
+}
+
+// New : dir 에 체크포인트를 저장하는 Checkpointer (디렉터리가 없으면 생성)
+// keep 개의 최근 체크포인트만 남기고 오래된 것은 지웁니다. clk 가 *clock.Virtual 이면 재개할 때 시각을 복원합니다.
+func New(dir string, keep int, seed uint64, clk clock.Clock, up *user.UserPool, sm *user.SessionManager, lc *controller.LoadController) (*Checkpointer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("checkpoint dir %s: %w", dir, err)
+	}
+	c := &Checkpointer{
+		dir:      dir,
+		keep:     max(keep, 1),
+		seed:     seed,
+		clock:    clk,
+		users:    up,
+		sessions: sm,
+		load:     lc,
+	}
+	// 이어서 번호를 매기도록 남아 있는 체크포인트의 마지막 번호부터 시작
+	names, err := c.list()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) > 0 {
+		c.seq, _ = parseSeq(names[len(names)-1])
+	}
+	return c, nil
+}
+
+// Dir : 체크포인트 디렉터리
+func (c *Checkpointer) Dir() string {
+	return c.dir
+}
+
+// Save : 현재 상태를 새 체크포인트로 저장하고 LATEST 를 바꿉니다.
+func (c *Checkpointer) Save() (*Meta, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seq := c.seq + 1
+	name := checkpointName(seq)
+	tmp := filepath.Join(c.dir, "."+name+".tmp")
+	os.RemoveAll(tmp)
+	if err := os.Mkdir(tmp, 0o755); err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", name, err)
+	}
+
+	meta, err := c.write(tmp, seq)
+	if err == nil {
+		err = os.Rename(tmp, filepath.Join(c.dir, name))
+	}
+	if err == nil {
+		err = writeFileAtomic(filepath.Join(c.dir, latestFile), []byte(name+"\n"))
+	}
+	if err != nil {
+		os.RemoveAll(tmp)
+		return nil, fmt.Errorf("checkpoint %s: %w", name, err)
+	}
+	c.seq = seq
+
+	if err := c.prune(); err != nil {
+		logging.Printf("[CHECKPOINT] %v\n", err)
+	}
+	return meta, nil
+}
+
+// write : dir 에 유저 / 세션 / meta.json 을 씁니다.
+func (c *Checkpointer) write(dir string, seq int) (*Meta, error) {
+	meta := &Meta{
+		Version: metaVersion,
+		Seq:     seq,
+		TakenAt: time.Now().UTC(),
+		Seed:    c.seed,
+		Clock:   c.clock.Now(),
+		Manager: c.sessions.State(),
+		Load:    c.load.State(),
+	}
+
+	if err := c.users.Save(filepath.Join(dir, usersFile)); err != nil {
+		return nil, err
+	}
+	meta.Users = c.users.TotalCount()
+	rngState, err := c.users.RandState()
+	if err != nil {
+		return nil, fmt.Errorf("encode user pool rng: %w", err)
+	}
+	meta.UserRNG = rngState
+
+	store, err := user.OpenBoltStore(filepath.Join(dir, sessionsFile))
+	if err != nil {
+		return nil, err
+	}
+	n, err := c.sessions.Save(store)
+	if err := errors.Join(err, store.Close()); err != nil {
+		return nil, err
+	}
+	meta.Sessions = n
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", metaFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, metaFile), append(data, '\n'), 0o644); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// Resume : 마지막 체크포인트를 불러와 시계 / 유저 풀 / 세션 / 카운터를 복원합니다.
+// 유저를 새로 만들기 전 (유저 풀이 비어 있을 때), 생성을 시작하기 전에 호출합니다.
+// 체크포인트를 만든 seed 와 지금의 seed 가 다르면 이어지지 않으므로 오류를 반환합니다.
+func (c *Checkpointer) Resume() (*Meta, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name, err := c.latest()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(c.dir, name)
+	meta, err := readMeta(dir)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Seed != c.seed {
+		return nil, fmt.Errorf("checkpoint %s was taken with seed %d, this run uses seed %d", name, meta.Seed, c.seed)
+	}
+	if n := c.users.TotalCount(); n != 0 {
+		return nil, fmt.Errorf("checkpoint %s: user pool already has %d users", name, n)
+	}
+
+	// 가상 시계는 체크포인트 시각부터 이어서 흐름 (실시간 실행은 꺼져 있던 시간만큼 건너뜀)
+	if vclk, ok := c.clock.(*clock.Virtual); ok {
+		vclk.Set(meta.Clock)
+	}
+
+	if _, err := c.users.Load(filepath.Join(dir, usersFile)); err != nil {
+		return nil, err
+	}
+	if err := c.users.SetRandState(meta.UserRNG); err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", name, err)
+	}
+
+	store, err := user.OpenBoltStore(filepath.Join(dir, sessionsFile))
+	if err != nil {
+		return nil, err
+	}
+	restored, skipped, err := c.sessions.Restore(store)
+	if err := errors.Join(err, store.Close()); err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		// 유저 프로필과 세션을 같은 시점에 저장하므로 정상이라면 건너뛰는 세션이 없음
+		logging.Printf("[CHECKPOINT] %s: skipped %d of %d sessions\n", name, skipped, restored+skipped)
+	}
+
+	c.sessions.SetState(meta.Manager)
+	c.load.SetState(meta.Load)
+	c.seq = max(c.seq, meta.Seq)
+	return meta, nil
+}
+
+// =======================
+// Helpers
+// =======================
+
+// latest : LATEST 가 가리키는 체크포인트 이름
+func (c *Checkpointer) latest() (string, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, latestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no checkpoint in %s", c.dir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", latestFile, err)
+	}
+	name := strings.TrimSpace(string(data))
+	if _, ok := parseSeq(name); !ok {
+		return "", fmt.Errorf("%s: invalid checkpoint name %q", filepath.Join(c.dir, latestFile), name)
+	}
+	return name, nil
+}
+
+// list : 완성된 체크포인트 이름 (번호 순)
+func (c *Checkpointer) list() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint dir %s: %w", c.dir, err)
+	}
+	var names []string
+	for _, e := range entries {
+		if _, ok := parseSeq(e.Name()); ok && e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// prune : 최근 keep 개를 남기고 오래된 체크포인트를 지웁니다. (LATEST 가 가리키는 체크포인트는 남김)
+func (c *Checkpointer) prune() error {
+	names, err := c.list()
+	if err != nil {
+		return err
+	}
+	latest, _ := c.latest()
+	var errs []error
+	for _, name := range names[:max(len(names)-c.keep, 0)] {
+		if name == latest {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(c.dir, name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func readMeta(dir string) (*Meta, error) {
+	data, err := os.ReadFile(filepath.Join(dir, metaFile))
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+	var meta Meta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Join(dir, metaFile), err)
+	}
+	if meta.Version != metaVersion {
+		return nil, fmt.Errorf("%s: unsupported version %d (want %d)", filepath.Join(dir, metaFile), meta.Version, metaVersion)
+	}
+	return &meta, nil
+}
+
+// checkpointName : 이름 순서가 번호 순서와 같도록 0 으로 채운 이름
+func checkpointName(seq int) string {
+	return fmt.Sprintf("%s%08d", namePrefix, seq)
+}
+
+func parseSeq(name string) (int, bool) {
+	v, ok := strings.CutPrefix(name, namePrefix)
+	if !ok {
+		return 0, false
+	}
+	seq, err := strconv.Atoi(v)
+	return seq, err == nil && seq > 0
+}
+
+// writeFileAtomic : 임시 파일에 쓴 뒤 rename 해서 path 를 바꿉니다.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
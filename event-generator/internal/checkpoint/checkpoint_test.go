@@ -0,0 +1,150 @@
+package checkpoint_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"event-generator/internal/checkpoint"
+	"event-generator/internal/clock"
+	"event-generator/internal/controller"
+	"event-generator/internal/fsm"
+	"event-generator/internal/generator"
+	"event-generator/internal/metrics"
+	"event-generator/internal/queue"
+	"event-generator/internal/rng"
+	"event-generator/internal/sink"
+	"event-generator/internal/user"
+	"event-generator/internal/worker"
+)
+
+const initialUsers = 200
+
+// TestResumeMatchesUninterrupted : seed 모드에서 N/2 에서 저장하고 재개해 N 까지 생성한 출력이
+// 한 번에 N 까지 생성한 출력과 바이트 단위로 같아야 합니다.
+func TestResumeMatchesUninterrupted(t *testing.T) {
+	const (
+		seed   = 42
+		events = 4000
+	)
+
+	want := newPipeline(t, seed).run(t, events)
+
+	dir := t.TempDir()
+	first := newPipeline(t, seed)
+	got := first.run(t, events/2)
+	if _, err := first.checkpointer(t, dir).Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	second := newPipeline(t, seed)
+	if _, err := second.checkpointer(t, dir).Resume(); err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, second.run(t, events)...)
+
+	if !bytes.Equal(got, want) {
+		gl, wl := bytes.Split(got, []byte("\n")), bytes.Split(want, []byte("\n"))
+		for i := range min(len(gl), len(wl)) {
+			if !bytes.Equal(gl[i], wl[i]) {
+				t.Fatalf("resumed output differs at event %d:\n got: %s\nwant: %s", i, gl[i], wl[i])
+			}
+		}
+		t.Fatalf("resumed output has %d lines, uninterrupted run has %d", len(gl), len(wl))
+	}
+}
+
+// TestResumeSeedMismatch : 다른 seed 로 만든 체크포인트에서는 재개하지 않아야 합니다.
+func TestResumeSeedMismatch(t *testing.T) {
+	dir := t.TempDir()
+	first := newPipeline(t, 42)
+	first.run(t, 500)
+	if _, err := first.checkpointer(t, dir).Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	second := newPipeline(t, 43)
+	_, err := second.checkpointer(t, dir).Resume()
+	if err == nil || !strings.Contains(err.Error(), "seed") {
+		t.Fatalf("resume with a different seed: got %v, want a seed mismatch error", err)
+	}
+	if n := second.up.TotalCount(); n != 0 {
+		t.Fatalf("failed resume loaded %d users", n)
+	}
+}
+
+// =======================
+// Helpers
+// =======================
+
+// pipeline : main 의 seed 모드와 같은 구성 (가상 시계, 시드 기반 유저 풀, 워커 1개, 메모리 Sink)
+type pipeline struct {
+	seed uint64
+	vclk *clock.Virtual
+	up   *user.UserPool
+	sm   *user.SessionManager
+	lc   *controller.LoadController
+	q    *queue.EventQueue
+	m    metrics.Metrics
+}
+
+// newPipeline : 유저를 만들지 않은 상태의 파이프라인 (처음 실행하면 run 에서, 재개하면 Resume 에서 유저를 채움)
+func newPipeline(t *testing.T, seed uint64) *pipeline {
+	t.Helper()
+	vclk := clock.NewVirtual(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	rngs := rng.NewFactory(seed)
+	catalog := generator.DefaultCatalog()
+	up := user.NewUserPool(rngs.Source("user_pool"), user.DefaultProfileDistribution(), catalog.CountryNames(), user.DefaultPersonas(), vclk)
+	m := metrics.NewInMemory()
+	q, err := queue.New(queue.Options{Capacity: 1024, Policy: queue.PolicyBlock, Metrics: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &pipeline{seed: seed, vclk: vclk, up: up, q: q, m: m}
+	p.sm = user.NewSessionManager(up, fsm.NewSimpleFSM(nil), generator.NewPayloadGenerator(catalog), q, m, 30*time.Minute, 1, vclk, rngs)
+	p.lc = controller.NewLoadController(50, 1, 10*time.Millisecond, up, p.sm, vclk)
+	return p
+}
+
+func (p *pipeline) checkpointer(t *testing.T, dir string) *checkpoint.Checkpointer {
+	t.Helper()
+	c, err := checkpoint.New(dir, 2, p.seed, p.vclk, p.up, p.sm, p.lc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// run : 누적 생성 수가 events 에 이를 때까지 RunSequential 을 실행하고, Sink 가 받은 이벤트를 NDJSON 으로 반환합니다.
+// main 과 같이 처음 실행이면 초기 유저를 만들고, 재개한 경우에는 부족한 만큼만 채웁니다. 파이프라인마다 한 번만 실행합니다.
+func (p *pipeline) run(t *testing.T, events int64) []byte {
+	t.Helper()
+	p.up.EnsureUsers(initialUsers)
+
+	out := sink.NewMemorySink()
+	w := worker.NewWorker(0, p.q.C(), p.m, out)
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		w.Run(context.Background())
+	}()
+
+	p.lc.SetMaxEvents(events)
+	p.lc.RunSequential(p.vclk)
+	if _, err := p.q.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-workerDone
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range out.Events() {
+		if err := enc.Encode(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,79 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 은 이벤트 타임스탬프와 세션 만료 계산에 사용하는 시간 소스입니다.
+// 실시간 실행에서는 Real, 재현 가능한(seed) 실행에서는 Virtual 을 사용합니다.
+type Clock interface {
+	Now() time.Time
+}
+
+// =======================
+// Real
+// =======================
+
+// Real : 시스템 시간을 그대로 사용하는 Clock
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// =======================
+// Virtual
+// =======================
+
+// Virtual : 명시적으로 Advance 할 때만 흐르는 가상 시계
+// 여러 고루틴에서 Now 를 읽을 수 있도록 뮤텍스로 보호합니다.
+type Virtual struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+func NewVirtual(start time.Time) *Virtual {
+	return &Virtual{now: start}
+}
+
+func (v *Virtual) Now() time.Time {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.now
+}
+
+// Advance : 가상 시간을 d 만큼 진행시키고 진행 후 시각을 반환합니다.
+func (v *Virtual) Advance(d time.Duration) time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.now = v.now.Add(d)
+	return v.now
+}
+
+// Set : 가상 시간을 t 로 맞춥니다.
+func (v *Virtual) Set(t time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.now = t
+}
+
+// =======================
+// Scaled
+// =======================
+
+// Scaled : start 부터 실제 시간의 speed 배로 흐르는 시계
+// 벤치마크 / 동시성 검사에서 체류 시간이 지난 세션의 다음 행동과 만료가 실제 실행처럼 섞여 일어나게 할 때 사용합니다.
+type Scaled struct {
+	start time.Time
+	wall  time.Time
+	speed float64
+}
+
+func NewScaled(start time.Time, speed float64) *Scaled {
+	return &Scaled{start: start, wall: time.Now(), speed: speed}
+}
+
+func (c *Scaled) Now() time.Time {
+	return c.start.Add(time.Duration(float64(time.Since(c.wall)) * c.speed))
+}
@@ -0,0 +1,554 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"event-generator/internal/controller"
+	"event-generator/internal/queue"
+	"event-generator/internal/sink"
+	"event-generator/internal/user"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =======================
+// Config
+// =======================
+
+// Config 는 이벤트 생성기의 전체 실행 설정입니다.
+// 우선순위: 기본값 < 설정 파일(YAML/JSON) < 환경 변수 < CLI 플래그
+type Config struct {
+	Run        RunConfig        `json:"run" yaml:"run"`
+	Load       LoadConfig       `json:"load" yaml:"load"`
+	Users      UsersConfig      `json:"users" yaml:"users"`
+	Session    SessionConfig    `json:"session" yaml:"session"`
+	Checkpoint CheckpointConfig `json:"checkpoint" yaml:"checkpoint"`
+	FSM        FSMConfig        `json:"fsm" yaml:"fsm"`
+	Catalog    CatalogConfig    `json:"catalog" yaml:"catalog"`
+	Channel    ChannelConfig    `json:"channel" yaml:"channel"`
+	Sink       SinkConfig       `json:"sink" yaml:"sink"`
+	Kafka      KafkaConfig      `json:"kafka" yaml:"kafka"`
+	Serializer SerializerConfig `json:"serializer" yaml:"serializer"`
+	Worker     WorkerConfig     `json:"worker" yaml:"worker"`
+	Metrics    MetricsConfig    `json:"metrics" yaml:"metrics"`
+	Admin      AdminConfig      `json:"admin" yaml:"admin"`
+	Shutdown   ShutdownConfig   `json:"shutdown" yaml:"shutdown"`
+
+	warnings []string // normalize 가 설정 값을 바꾼 이유 (Warnings)
+}
+
+// RunConfig : 실행 모드 설정
+//
+// Seed 가 0 이 아니면 재현 가능(deterministic) 모드로 실행합니다.
+// 같은 Seed / 설정 / Events 로 실행하면 이벤트 ID 와 타임스탬프까지 바이트 단위로 동일한 결과가 나오며,
+// 이를 위해 Start 부터 흐르는 가상 시계를 사용하고 생성 고루틴과 워커는 각각 1개로 고정됩니다.
+//
+// End 를 지정하면 backfill 모드로 실행합니다. Start ~ End 의 과거 구간을 가상 시계로 시뮬레이션하며
+// (load.shape 도 가상 시각 기준으로 적용) 실제 시간과 무관하게 Sink 가 받아들이는 속도로 최대한 빠르게 생성하고,
+// 가상 시계가 End 에 도달하면 종료합니다. Seed 가 0 이면 워커 수는 설정대로 유지합니다.
+type RunConfig struct {
+	Seed   uint64    `json:"seed" yaml:"seed"`
+	Events int64     `json:"events" yaml:"events"`               // 생성할 이벤트 수 (0 이면 무제한)
+	Start  Timestamp `json:"start" yaml:"start"`                 // seed / backfill 모드 가상 시계 시작 시각
+	End    Timestamp `json:"end,omitempty" yaml:"end,omitempty"` // backfill 종료 시각 (비어 있으면 backfill 아님)
+}
+
+// LoadConfig : LoadController 설정
+type LoadConfig struct {
+	TargetTPS    float64     `json:"target_tps" yaml:"target_tps"` // 소수 가능 (예: 0.5 = 2초에 1개)
+	Goroutines   int         `json:"goroutines" yaml:"goroutines"` // Step()을 호출하는 고루틴 수
+	TickInterval Duration    `json:"tick_interval" yaml:"tick_interval"`
+	Shape        ShapeConfig `json:"shape" yaml:"shape"` // 시간대별 트래픽 모양 (비어 있으면 target_tps 고정)
+}
+
+// ShapeConfig : target_tps 에 곱할 시간대별 배수
+// daily / weekly / daily_at spike 는 Timezone 현지 시각 기준, steps / after spike 는 실행 시작 후 경과 시간 기준입니다.
+type ShapeConfig struct {
+	Timezone string             `json:"timezone,omitempty" yaml:"timezone,omitempty"` // IANA 이름 (비어 있으면 UTC)
+	Daily    *DailyShapeConfig  `json:"daily,omitempty" yaml:"daily,omitempty"`
+	Weekly   map[string]float64 `json:"weekly,omitempty" yaml:"weekly,omitempty"` // mon..sun → 배수
+	Steps    []StepShapeConfig  `json:"steps,omitempty" yaml:"steps,omitempty"`
+	Spikes   []SpikeShapeConfig `json:"spikes,omitempty" yaml:"spikes,omitempty"`
+}
+
+// DailyShapeConfig : 하루 주기 사인 곡선 (1 ± amplitude, peak_hour 에 최대)
+type DailyShapeConfig struct {
+	Amplitude float64 `json:"amplitude" yaml:"amplitude"`
+	PeakHour  float64 `json:"peak_hour" yaml:"peak_hour"`
+}
+
+// StepShapeConfig : after 경과 후 multiplier 로 변경 (ramp 동안 선형 변화)
+type StepShapeConfig struct {
+	After      Duration `json:"after" yaml:"after"`
+	Multiplier float64  `json:"multiplier" yaml:"multiplier"`
+	Ramp       Duration `json:"ramp,omitempty" yaml:"ramp,omitempty"`
+}
+
+// SpikeShapeConfig : 플래시 세일 구간 (after 경과 후 한 번 또는 매일 daily_at "HH:MM" 에 반복)
+type SpikeShapeConfig struct {
+	After      Duration `json:"after,omitempty" yaml:"after,omitempty"`
+	DailyAt    string   `json:"daily_at,omitempty" yaml:"daily_at,omitempty"`
+	Duration   Duration `json:"duration" yaml:"duration"`
+	Multiplier float64  `json:"multiplier" yaml:"multiplier"`
+	Ramp       Duration `json:"ramp,omitempty" yaml:"ramp,omitempty"`
+}
+
+// UsersConfig : UserPool 설정
+//
+// Profiles 경로가 있으면 시작 시 저장된 유저 프로필을 불러오고 종료 시 다시 저장하므로
+// 실행이 바뀌어도 같은 유저가 재방문합니다. 부족한 유저는 Distribution 분포로 새로 만듭니다.
+type UsersConfig struct {
+	Initial      int                      `json:"initial" yaml:"initial"`                       // 시작 시 확보할 유저 수
+	Profiles     string                   `json:"profiles,omitempty" yaml:"profiles,omitempty"` // 유저 프로필 저장 파일 (비어 있으면 저장하지 않음)
+	Distribution user.ProfileDistribution `json:"distribution" yaml:"distribution"`
+	Personas     []user.Persona           `json:"personas" yaml:"personas"` // 행동 유형과 배정 비율 (weight)
+}
+
+// SessionConfig : SessionManager 설정
+type SessionConfig struct {
+	TTL Duration `json:"ttl" yaml:"ttl"`
+	// 세션 저장소 조각 수 (Step 고루틴이 많을수록 늘림)
+	// 시드 모드 출력 순서가 조각 수에 따라 달라지므로 CPU 수가 아닌 고정 기본값을 사용합니다.
+	Shards int `json:"shards" yaml:"shards"`
+	// 진행 중인 세션 저장 파일 (bbolt, 비어 있으면 저장하지 않음)
+	// 시작 시 불러와 이어서 진행하고 종료 시 다시 저장합니다. 유저 상태도 이어지도록 users.profiles 와 함께 사용합니다.
+	Store string `json:"store,omitempty" yaml:"store,omitempty"`
+}
+
+// CheckpointConfig : 체크포인트 설정
+// Dir 을 지정하면 Interval 마다 (그리고 종료 시) 유저 풀 / 진행 중인 세션 / 난수 스트림 / 시계 / 카운터를 저장하고,
+// Resume 이면 마지막 체크포인트부터 이어서 생성합니다. (users.profiles / session.store 대신 체크포인트에서 복원)
+type CheckpointConfig struct {
+	Dir      string   `json:"dir,omitempty" yaml:"dir,omitempty"` // 체크포인트 디렉터리 (비어 있으면 사용하지 않음)
+	Interval Duration `json:"interval" yaml:"interval"`           // 실제 시간 기준 저장 간격 (0 이면 종료 시에만 저장)
+	Keep     int      `json:"keep" yaml:"keep"`                   // 보관할 최근 체크포인트 수
+	Resume   bool     `json:"resume" yaml:"resume"`               // 시작 시 마지막 체크포인트에서 재개
+}
+
+// FSMConfig : 상태 전이 모델 설정
+type FSMConfig struct {
+	Model string `json:"model,omitempty" yaml:"model,omitempty"` // YAML/JSON 모델 파일 경로 (비어 있으면 기본 그래프)
+}
+
+// CatalogConfig : 상품 카탈로그 설정
+type CatalogConfig struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"` // JSON/CSV 카탈로그 파일 경로 (비어 있으면 기본 카탈로그)
+}
+
+// ChannelConfig : SessionManager → Worker 이벤트 채널 설정
+//
+// Policy 는 채널이 가득 찼을 때의 처리 방식입니다.
+// block 은 생산자가 대기해 처리량이 조용히 떨어지고, drop_newest / drop_oldest 는 이벤트를 버리며,
+// spill 은 로컬 디스크 큐에 적재했다가 자리가 나면 순서대로 되돌립니다.
+// seed 모드에서 block 외의 정책을 쓰면 유실 / 적재 시점이 타이밍에 좌우되어 결과가 재현되지 않을 수 있습니다.
+type ChannelConfig struct {
+	Buffer    int     `json:"buffer" yaml:"buffer"`
+	Policy    string  `json:"policy" yaml:"policy"`                             // block | drop_newest | drop_oldest | spill
+	WarnRatio float64 `json:"warn_ratio" yaml:"warn_ratio"`                     // 채널이 이 비율 이상 차면 경고 (0 이면 끔)
+	SpillPath string  `json:"spill_path,omitempty" yaml:"spill_path,omitempty"` // spill 디스크 큐 파일 (비어 있으면 임시 파일)
+	SpillMax  int64   `json:"spill_max,omitempty" yaml:"spill_max,omitempty"`   // 디스크 큐 최대 이벤트 수 (0 이면 무제한)
+}
+
+// SinkConfig : 이벤트 출력 대상 설정
+// Type: kafka | file | stdout
+type SinkConfig struct {
+	Type   string           `json:"type" yaml:"type"`
+	Path   string           `json:"path,omitempty" yaml:"path,omitempty"` // file sink 출력 경로 (NDJSON)
+	Faults sink.FaultConfig `json:"faults" yaml:"faults"`                 // 장애 주입 (실행 중 admin API 로 켜고 끌 수 있음)
+}
+
+// PipelineTopic : flink-kafka-clickhouse 잡이 구독하는 토픽
+const PipelineTopic = "user_events"
+
+// KafkaConfig : Kafka 프로듀서 설정
+type KafkaConfig struct {
+	Brokers []string `json:"brokers" yaml:"brokers"`
+	Topic   string   `json:"topic" yaml:"topic"`
+}
+
+// SerializerConfig : Kafka 메시지 직렬화 설정
+// Format: json | avro | protobuf
+//
+// avro/protobuf 는 Registry 경로가 있으면 시작 시 "<topic>-value" subject 로 스키마를 등록하고
+// Confluent wire format(magic byte + 스키마 ID) 헤더를 붙여 전송합니다.
+//
+// flink-kafka-clickhouse 잡은 PipelineTopic 을 Jackson 으로 JSON 파싱만 하므로, 이 토픽에는 json 만 허용합니다.
+// avro/protobuf 는 잡에 디시리얼라이저가 추가될 때까지 다른 토픽 (호환성 검증용 등) 에서만 사용할 수 있습니다.
+type SerializerConfig struct {
+	Format        string `json:"format" yaml:"format"`
+	Registry      string `json:"registry,omitempty" yaml:"registry,omitempty"`           // 파일 기반 스키마 레지스트리 경로
+	Compatibility string `json:"compatibility,omitempty" yaml:"compatibility,omitempty"` // 레지스트리 호환성 레벨 (비어 있으면 레지스트리 설정 유지)
+}
+
+// WorkerConfig : 이벤트 전송 워커 설정
+type WorkerConfig struct {
+	Count int `json:"count" yaml:"count"`
+}
+
+// MetricsConfig : 메트릭 출력 설정
+type MetricsConfig struct {
+	Interval Duration `json:"interval" yaml:"interval"`
+	Listen   string   `json:"listen" yaml:"listen"` // Prometheus /metrics 주소 (비어 있으면 비활성화)
+}
+
+// AdminConfig : 실행 중 제어 HTTP API 설정
+// 인증이 없으므로 외부에 노출하지 말고 127.0.0.1 등 내부 주소로만 여세요.
+type AdminConfig struct {
+	Listen string `json:"listen" yaml:"listen"` // admin API 주소 (비어 있으면 비활성화)
+}
+
+// ShutdownConfig : 종료 설정
+// 신호를 받으면 생성을 멈추고 채널 / 디스크 큐에 남은 이벤트를 모두 전송할 때까지 최대 Timeout 동안 기다립니다.
+type ShutdownConfig struct {
+	Timeout Duration `json:"timeout" yaml:"timeout"`
+}
+
+// Default : 기존 하드코딩 값과 동일한 기본 설정
+func Default() *Config {
+	return &Config{
+		Run: RunConfig{
+			Start: Timestamp(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		Load: LoadConfig{
+			TargetTPS:    20000,
+			Goroutines:   12,
+			TickInterval: Duration(20 * time.Millisecond),
+		},
+		Users: UsersConfig{
+			Initial:      100000,
+			Distribution: defaultDistribution(),
+			Personas:     user.DefaultPersonas(),
+		},
+		Session: SessionConfig{
+			TTL:    Duration(30 * time.Minute),
+			Shards: 64,
+		},
+		Checkpoint: CheckpointConfig{
+			Interval: Duration(time.Minute),
+			Keep:     3,
+		},
+		Channel: ChannelConfig{
+			Buffer:    100000,
+			Policy:    queue.PolicyBlock,
+			WarnRatio: 0.8,
+		},
+		Sink: SinkConfig{
+			Type: "kafka",
+		},
+		Kafka: KafkaConfig{
+			Brokers: []string{"localhost:9092"},
+			Topic:   PipelineTopic,
+		},
+		Serializer: SerializerConfig{
+			Format: "json",
+		},
+		Worker: WorkerConfig{
+			Count: 12,
+		},
+		Metrics: MetricsConfig{
+			Interval: Duration(1 * time.Second),
+			Listen:   ":2112",
+		},
+		Shutdown: ShutdownConfig{
+			Timeout: Duration(30 * time.Second),
+		},
+	}
+}
+
+// =======================
+// File loading
+// =======================
+
+// LoadFile : 확장자에 따라 YAML 또는 JSON 설정 파일을 cfg 위에 덮어씁니다.
+// 파일에 없는 항목은 기존 값(기본값)을 유지합니다.
+func LoadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(cfg); err != nil {
+			return fmt.Errorf("parse config %s: %w", path, err)
+		}
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("parse config %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("unsupported config format: %s (use .yaml, .yml or .json)", path)
+	}
+
+	return nil
+}
+
+// =======================
+// Validation
+// =======================
+
+// Validate : 실행 전에 설정 값의 유효성을 검사합니다.
+// 여러 오류가 있으면 모두 모아서 반환합니다.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Run.Events < 0 {
+		errs = append(errs, fmt.Errorf("run.events must be >= 0 (got %d)", c.Run.Events))
+	}
+	if c.Run.Seed != 0 && c.Run.Start.Std().IsZero() {
+		errs = append(errs, errors.New("run.start is required when run.seed is set"))
+	}
+	if c.Backfill() {
+		if c.Run.Start.Std().IsZero() {
+			errs = append(errs, errors.New("run.start is required when run.end is set"))
+		} else if !c.Run.End.Std().After(c.Run.Start.Std()) {
+			errs = append(errs, fmt.Errorf("run.end must be after run.start (got %s ~ %s)", c.Run.Start, c.Run.End))
+		}
+	}
+	if c.Load.TargetTPS <= 0 {
+		errs = append(errs, fmt.Errorf("load.target_tps must be > 0 (got %g)", c.Load.TargetTPS))
+	}
+	if c.Load.Goroutines <= 0 {
+		errs = append(errs, fmt.Errorf("load.goroutines must be > 0 (got %d)", c.Load.Goroutines))
+	}
+	if c.Load.TickInterval.Std() <= 0 || c.Load.TickInterval.Std() > time.Second {
+		errs = append(errs, fmt.Errorf("load.tick_interval must be in (0, 1s] (got %s)", c.Load.TickInterval))
+	}
+	if _, err := c.Load.Shape.Build(); err != nil {
+		errs = append(errs, fmt.Errorf("load.shape: %w", err))
+	}
+	if c.Users.Initial < 0 {
+		errs = append(errs, fmt.Errorf("users.initial must be >= 0 (got %d)", c.Users.Initial))
+	}
+	if err := c.Users.Distribution.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("users.distribution: %w", err))
+	}
+	if err := user.ValidatePersonas(c.Users.Personas); err != nil {
+		errs = append(errs, fmt.Errorf("users.personas: %w", err))
+	}
+	if c.Session.TTL.Std() <= 0 {
+		errs = append(errs, fmt.Errorf("session.ttl must be > 0 (got %s)", c.Session.TTL))
+	}
+	if c.Session.Shards < 1 {
+		errs = append(errs, fmt.Errorf("session.shards must be >= 1 (got %d)", c.Session.Shards))
+	}
+	if c.Checkpoint.Interval.Std() < 0 {
+		errs = append(errs, fmt.Errorf("checkpoint.interval must be >= 0 (got %s)", c.Checkpoint.Interval))
+	}
+	if c.Checkpoint.Keep < 1 {
+		errs = append(errs, fmt.Errorf("checkpoint.keep must be >= 1 (got %d)", c.Checkpoint.Keep))
+	}
+	if c.Checkpoint.Resume && c.Checkpoint.Dir == "" {
+		errs = append(errs, errors.New("resume requires checkpoint.dir"))
+	}
+	if c.Channel.Buffer < 0 {
+		errs = append(errs, fmt.Errorf("channel.buffer must be >= 0 (got %d)", c.Channel.Buffer))
+	}
+	if !slices.Contains(queue.Policies, c.Channel.Policy) {
+		errs = append(errs, fmt.Errorf("channel.policy must be one of %s (got %q)", strings.Join(queue.Policies, ", "), c.Channel.Policy))
+	}
+	if c.Channel.WarnRatio < 0 || c.Channel.WarnRatio > 1 {
+		errs = append(errs, fmt.Errorf("channel.warn_ratio must be in [0, 1] (got %g)", c.Channel.WarnRatio))
+	}
+	if c.Channel.SpillMax < 0 {
+		errs = append(errs, fmt.Errorf("channel.spill_max must be >= 0 (got %d)", c.Channel.SpillMax))
+	}
+	switch c.Sink.Type {
+	case "kafka":
+		if len(c.Kafka.Brokers) == 0 {
+			errs = append(errs, errors.New("kafka.brokers must not be empty"))
+		}
+		for _, b := range c.Kafka.Brokers {
+			if strings.TrimSpace(b) == "" {
+				errs = append(errs, errors.New("kafka.brokers must not contain empty addresses"))
+				break
+			}
+		}
+		if c.Kafka.Topic == "" {
+			errs = append(errs, errors.New("kafka.topic must not be empty"))
+		}
+	case "file":
+		if c.Sink.Path == "" {
+			errs = append(errs, errors.New("sink.path is required for the file sink"))
+		}
+	case "stdout":
+	default:
+		errs = append(errs, fmt.Errorf("sink.type must be one of kafka, file, stdout (got %q)", c.Sink.Type))
+	}
+	if err := c.Sink.Faults.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("sink.faults: %w", err))
+	}
+	switch c.Serializer.Format {
+	case "json":
+	case "avro", "protobuf":
+		if c.Sink.Type != "kafka" {
+			errs = append(errs, fmt.Errorf("serializer.format %s requires the kafka sink (file/stdout sinks write JSON)", c.Serializer.Format))
+		} else if c.Kafka.Topic == PipelineTopic {
+			errs = append(errs, fmt.Errorf("serializer.format %s cannot be used on kafka.topic %q: the Flink consumer only parses JSON (use a different topic)", c.Serializer.Format, PipelineTopic))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("serializer.format must be one of json, avro, protobuf (got %q)", c.Serializer.Format))
+	}
+	switch c.Serializer.Compatibility {
+	case "", "NONE", "BACKWARD", "FORWARD", "FULL", "BACKWARD_TRANSITIVE", "FORWARD_TRANSITIVE", "FULL_TRANSITIVE":
+	default:
+		errs = append(errs, fmt.Errorf("serializer.compatibility must be a schema registry compatibility level (got %q)", c.Serializer.Compatibility))
+	}
+	if c.Worker.Count <= 0 {
+		errs = append(errs, fmt.Errorf("worker.count must be > 0 (got %d)", c.Worker.Count))
+	}
+	if c.Metrics.Interval.Std() <= 0 {
+		errs = append(errs, fmt.Errorf("metrics.interval must be > 0 (got %s)", c.Metrics.Interval))
+	}
+	if c.Shutdown.Timeout.Std() <= 0 {
+		errs = append(errs, fmt.Errorf("shutdown.timeout must be > 0 (got %s)", c.Shutdown.Timeout))
+	}
+
+	return errors.Join(errs...)
+}
+
+// weekdays : weekly 키 → 요일
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Build : LoadController 가 사용하는 Shape 로 변환합니다. 설정이 비어 있으면 nil 을 반환합니다.
+func (s ShapeConfig) Build() (*controller.Shape, error) {
+	if s.Daily == nil && len(s.Weekly) == 0 && len(s.Steps) == 0 && len(s.Spikes) == 0 {
+		return nil, nil
+	}
+
+	var errs []error
+	shape := &controller.Shape{Location: time.UTC}
+	if s.Timezone != "" {
+		loc, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("timezone: %w", err))
+		} else {
+			shape.Location = loc
+		}
+	}
+	if s.Daily != nil {
+		shape.Daily = &controller.Daily{Amplitude: s.Daily.Amplitude, PeakHour: s.Daily.PeakHour}
+	}
+	if len(s.Weekly) > 0 {
+		shape.Weekly = make(map[time.Weekday]float64, len(s.Weekly))
+		for k, m := range s.Weekly {
+			day, ok := weekdays[strings.ToLower(k)]
+			if !ok {
+				errs = append(errs, fmt.Errorf("weekly: unknown day %q (use mon, tue, ..., sun)", k))
+				continue
+			}
+			shape.Weekly[day] = m
+		}
+	}
+	for _, st := range s.Steps {
+		shape.Steps = append(shape.Steps, controller.Step{After: st.After.Std(), Multiplier: st.Multiplier, Ramp: st.Ramp.Std()})
+	}
+	for i, sp := range s.Spikes {
+		spike := controller.Spike{After: sp.After.Std(), Duration: sp.Duration.Std(), Multiplier: sp.Multiplier, Ramp: sp.Ramp.Std()}
+		if sp.DailyAt != "" {
+			if sp.After != 0 {
+				errs = append(errs, fmt.Errorf("spikes[%d]: set either after or daily_at, not both", i))
+			}
+			at, err := time.Parse("15:04", sp.DailyAt)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("spikes[%d]: daily_at must be HH:MM (got %q)", i, sp.DailyAt))
+			}
+			spike.Daily = true
+			spike.DailyAt = time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute
+		}
+		shape.Spikes = append(shape.Spikes, spike)
+	}
+
+	if err := shape.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return shape, nil
+}
+
+// Deterministic : seed 모드 여부
+func (c *Config) Deterministic() bool {
+	return c.Run.Seed != 0
+}
+
+// Backfill : backfill 모드 여부 (run.end 지정)
+func (c *Config) Backfill() bool {
+	return !c.Run.End.Std().IsZero()
+}
+
+// VirtualClock : 가상 시계로 실행하는지 여부 (seed 또는 backfill 모드)
+// 가상 시계 실행은 단일 고루틴이 Step 마다 시계를 진행시키며 (LoadController.RunSequential) 최대한 빠르게 생성합니다.
+func (c *Config) VirtualClock() bool {
+	return c.Deterministic() || c.Backfill()
+}
+
+// defaultDistribution : 가중치 맵을 비운 기본 프로필 분포
+// YAML/JSON 디코더는 기존 맵에 키를 합치므로, 기본 맵을 미리 채워 두면 설정 파일에서 항목을 뺄 수 없습니다.
+// 비어 있는 맵은 normalize 에서 기본값으로 채웁니다.
+func defaultDistribution() user.ProfileDistribution {
+	d := user.DefaultProfileDistribution()
+	d.Devices = nil
+	d.LoyaltyTiers = nil
+	return d
+}
+
+// Warnings : 설정 과정에서 명시한 값이 바뀐 항목 (main 이 로그 출력 대상을 정한 뒤 [CONFIG] 로 출력)
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+// normalize : 모드에 따라 강제되는 값을 적용합니다.
+// explicit 는 설정 파일 / 환경 변수 / 플래그로 명시한 항목 (플래그 이름) 이며, 명시한 값을 바꾸면 경고를 남깁니다.
+func (c *Config) normalize(explicit map[string]bool) {
+	def := user.DefaultProfileDistribution()
+	if len(c.Users.Distribution.Devices) == 0 {
+		c.Users.Distribution.Devices = def.Devices
+	}
+	if len(c.Users.Distribution.LoyaltyTiers) == 0 {
+		c.Users.Distribution.LoyaltyTiers = def.LoyaltyTiers
+	}
+
+	if c.Deterministic() {
+		// 이벤트 순서를 고정하기 위해 생성/전송 모두 단일 고루틴으로 실행
+		if explicit["load.goroutines"] && c.Load.Goroutines != 1 {
+			c.warnings = append(c.warnings, fmt.Sprintf("load.goroutines=%d is ignored: seeded runs use a single generator goroutine", c.Load.Goroutines))
+		}
+		if explicit["worker.count"] && c.Worker.Count != 1 {
+			c.warnings = append(c.warnings, fmt.Sprintf("worker.count=%d is ignored: seeded runs use a single worker to keep the output order", c.Worker.Count))
+		}
+		c.Load.Goroutines = 1
+		c.Worker.Count = 1
+	}
+}
+
+// =======================
+// Printing
+// =======================
+
+// Print : 최종 적용된 설정을 YAML 형태로 출력합니다.
+func (c *Config) Print(w io.Writer) error {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "[CONFIG] effective configuration:\n%s", out)
+	return err
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// EnvPrefix : 환경 변수 오버라이드 접두사 (예: EVENTGEN_LOAD_TARGET_TPS=5000)
+const EnvPrefix = "EVENTGEN_"
+
+// =======================
+// Duration
+// =======================
+
+// Duration 은 "20ms", "30m" 같은 문자열로 YAML/JSON/플래그에서 읽고 쓸 수 있는 time.Duration 입니다.
+type Duration time.Duration
+
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d *Duration) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	return d.Set(string(b))
+}
+
+// =======================
+// Timestamp
+// =======================
+
+// Timestamp 는 RFC3339 문자열("2025-01-01T00:00:00Z")로 읽고 쓰는 시각입니다.
+type Timestamp time.Time
+
+func (t Timestamp) Std() time.Time {
+	return time.Time(t)
+}
+
+func (t Timestamp) String() string {
+	if time.Time(t).IsZero() {
+		return ""
+	}
+	return time.Time(t).Format(time.RFC3339)
+}
+
+// IsZero : 값이 없으면 YAML 출력에서 생략 (omitempty)
+func (t Timestamp) IsZero() bool {
+	return time.Time(t).IsZero()
+}
+
+func (t *Timestamp) Set(s string) error {
+	if s == "" {
+		*t = Timestamp{}
+		return nil
+	}
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return err
+	}
+	*t = Timestamp(v)
+	return nil
+}
+
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Timestamp) UnmarshalText(b []byte) error {
+	return t.Set(string(b))
+}
+
+// stringList : 콤마로 구분된 문자열 목록 플래그
+type stringList struct {
+	target *[]string
+}
+
+func (s stringList) String() string {
+	if s.target == nil {
+		return ""
+	}
+	return strings.Join(*s.target, ",")
+}
+
+func (s stringList) Set(v string) error {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	*s.target = out
+	return nil
+}
+
+// =======================
+// Flags & Env
+// =======================
+
+// bindFlags : 설정 필드를 플래그에 직접 연결합니다.
+// 플래그 이름의 '.' 과 '-' 를 '_' 로 바꾸고 대문자로 만든 값이 환경 변수 이름이 됩니다.
+func bindFlags(fs *flag.FlagSet, cfg *Config) {
+	fs.Uint64Var(&cfg.Run.Seed, "seed", cfg.Run.Seed, "random seed for a reproducible run (0 = non-deterministic)")
+	fs.Int64Var(&cfg.Run.Events, "events", cfg.Run.Events, "stop after generating this many events (0 = unlimited)")
+	fs.Var(&cfg.Run.Start, "start", "virtual clock start time for seeded and backfill runs (RFC3339)")
+	fs.Var(&cfg.Run.End, "end", "backfill: simulate traffic from -start until this time (RFC3339) as fast as the sink allows, then exit (empty = real-time run)")
+	fs.Float64Var(&cfg.Load.TargetTPS, "load.target-tps", cfg.Load.TargetTPS, "target events per second (fractional rates allowed)")
+	fs.IntVar(&cfg.Load.Goroutines, "load.goroutines", cfg.Load.Goroutines, "number of LoadController goroutines calling SessionManager.Step")
+	fs.Var(&cfg.Load.TickInterval, "load.tick-interval", "LoadController tick interval")
+	fs.IntVar(&cfg.Users.Initial, "users.initial", cfg.Users.Initial, "number of users created at startup")
+	fs.StringVar(&cfg.Users.Profiles, "users.profiles", cfg.Users.Profiles, "path to the persisted user profile file, loaded at startup and saved on shutdown (empty = not persisted)")
+	fs.Var(&cfg.Session.TTL, "session.ttl", "idle session TTL")
+	fs.IntVar(&cfg.Session.Shards, "session.shards", cfg.Session.Shards, "number of session store shards (lock stripes)")
+	fs.StringVar(&cfg.Session.Store, "session.store", cfg.Session.Store, "path to the persisted session store (bbolt), restored at startup and saved on shutdown (empty = not persisted)")
+	fs.StringVar(&cfg.Checkpoint.Dir, "checkpoint.dir", cfg.Checkpoint.Dir, "directory for periodic checkpoints of users, live sessions, RNG state, clock and counters (empty = disabled)")
+	fs.Var(&cfg.Checkpoint.Interval, "checkpoint.interval", "wall-clock interval between checkpoints (0 = only on shutdown)")
+	fs.IntVar(&cfg.Checkpoint.Keep, "checkpoint.keep", cfg.Checkpoint.Keep, "number of recent checkpoints to keep")
+	fs.BoolVar(&cfg.Checkpoint.Resume, "resume", cfg.Checkpoint.Resume, "continue from the latest checkpoint in checkpoint.dir")
+	fs.StringVar(&cfg.FSM.Model, "fsm.model", cfg.FSM.Model, "path to a YAML or JSON FSM model file (empty = built-in graph)")
+	fs.StringVar(&cfg.Catalog.Path, "catalog.path", cfg.Catalog.Path, "path to a JSON or CSV product catalog (empty = built-in catalog)")
+	fs.IntVar(&cfg.Channel.Buffer, "channel.buffer", cfg.Channel.Buffer, "event channel buffer size")
+	fs.StringVar(&cfg.Channel.Policy, "channel.policy", cfg.Channel.Policy, "backpressure policy when the event channel is full: block, drop_newest, drop_oldest or spill")
+	fs.Float64Var(&cfg.Channel.WarnRatio, "channel.warn-ratio", cfg.Channel.WarnRatio, "warn when the event channel is at least this full (0 = no warning)")
+	fs.StringVar(&cfg.Channel.SpillPath, "channel.spill-path", cfg.Channel.SpillPath, "disk queue file for the spill policy (empty = temporary file)")
+	fs.Int64Var(&cfg.Channel.SpillMax, "channel.spill-max", cfg.Channel.SpillMax, "maximum events held in the spill queue before new events are dropped (0 = unlimited)")
+	fs.StringVar(&cfg.Sink.Type, "sink.type", cfg.Sink.Type, "event sink: kafka, file or stdout")
+	fs.StringVar(&cfg.Sink.Path, "sink.path", cfg.Sink.Path, "output path for the file sink (newline-delimited JSON)")
+	fs.Var(stringList{&cfg.Kafka.Brokers}, "kafka.brokers", "comma separated Kafka broker addresses")
+	fs.StringVar(&cfg.Kafka.Topic, "kafka.topic", cfg.Kafka.Topic, "Kafka topic")
+	fs.StringVar(&cfg.Serializer.Format, "serializer.format", cfg.Serializer.Format, "Kafka message encoding: json, avro or protobuf")
+	fs.StringVar(&cfg.Serializer.Registry, "serializer.registry", cfg.Serializer.Registry, "path to the file-based schema registry (empty = no schema ID header)")
+	fs.StringVar(&cfg.Serializer.Compatibility, "serializer.compatibility", cfg.Serializer.Compatibility, "schema registry compatibility level, e.g. BACKWARD or FULL")
+	fs.IntVar(&cfg.Worker.Count, "worker.count", cfg.Worker.Count, "number of producer workers")
+	fs.Var(&cfg.Metrics.Interval, "metrics.interval", "metrics print interval")
+	fs.StringVar(&cfg.Metrics.Listen, "metrics.listen", cfg.Metrics.Listen, "address for the Prometheus /metrics endpoint (empty = disabled)")
+	fs.Var(&cfg.Shutdown.Timeout, "shutdown.timeout", "how long to wait for queued events to be delivered on shutdown")
+	fs.StringVar(&cfg.Admin.Listen, "admin.listen", cfg.Admin.Listen, "address for the runtime admin HTTP API, e.g. 127.0.0.1:2113 (empty = disabled)")
+}
+
+func envName(flagName string) string {
+	r := strings.NewReplacer(".", "_", "-", "_")
+	return EnvPrefix + strings.ToUpper(r.Replace(flagName))
+}
+
+// Parse : 기본값 → 설정 파일 → 환경 변수 → 플래그 순서로 설정을 구성하고 검증합니다.
+// 설정 파일 경로는 -config 플래그 또는 EVENTGEN_CONFIG 환경 변수로 지정합니다.
+func Parse(name string, args []string) (*Config, error) {
+	cfg := Default()
+	// 명시적으로 지정한 항목 (normalize 가 모드에 따라 값을 바꿀 때 경고 여부 판단)
+	explicit := make(map[string]bool)
+
+	path := os.Getenv(EnvPrefix + "CONFIG")
+	if p, ok := lookupConfigArg(args); ok {
+		path = p
+	}
+	if path != "" {
+		if err := LoadFile(path, cfg); err != nil {
+			return nil, err
+		}
+		def := Default()
+		explicit["load.goroutines"] = cfg.Load.Goroutines != def.Load.Goroutines
+		explicit["worker.count"] = cfg.Worker.Count != def.Worker.Count
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.String("config", path, "path to YAML or JSON config file (env "+EnvPrefix+"CONFIG)")
+	bindFlags(fs, cfg)
+
+	// 환경 변수 오버라이드
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" || envErr != nil {
+			return
+		}
+		if v, ok := os.LookupEnv(envName(f.Name)); ok {
+			if err := f.Value.Set(v); err != nil {
+				envErr = fmt.Errorf("invalid %s=%q: %w", envName(f.Name), v, err)
+				return
+			}
+			explicit[f.Name] = true
+		}
+	})
+	if envErr != nil {
+		return nil, envErr
+	}
+
+	// 플래그 오버라이드 (가장 높은 우선순위)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	cfg.normalize(explicit)
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// lookupConfigArg : 플래그 파싱 전에 -config 값만 먼저 찾습니다.
+func lookupConfigArg(args []string) (string, bool) {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			break
+		}
+		name := strings.TrimLeft(a, "-")
+		if name == a {
+			continue
+		}
+		if v, ok := strings.CutPrefix(name, "config="); ok {
+			return v, true
+		}
+		if name == "config" && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
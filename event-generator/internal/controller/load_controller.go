@@ -1,41 +1,250 @@
 package controller
 
 import (
+	"context"
+	"event-generator/internal/clock"
+	"event-generator/internal/logging"
 	"event-generator/internal/user"
 	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// 실행 중 제어 (admin API)
+// 기본 목표 TPS / shape / 일시 정지 / 유저 수 고정은 다른 고루틴에서 바꿀 수 있으며 다음 tick 부터 반영됩니다.
 type LoadController struct {
-	TargetTPS int
+	baseTPS atomic.Uint64 // 기본 목표 TPS (Shape 배수를 곱하기 전, 소수 가능, math.Float64bits)
+
+	// 시간대별 트래픽 모양 (nil 이면 기본 목표 TPS 고정)
+	shape   atomic.Pointer[activeShape]
+	clock   clock.Clock
+	current atomic.Uint64 // 이번 tick 의 목표 TPS (math.Float64bits)
+
+	paused    atomic.Bool
+	userLimit atomic.Int64 // 0 이 아니면 유저 풀 크기를 이 값으로 고정
+
+	// 실측 기반 발급 속도 보정
+	rate rateController
 
 	UserPool       *user.UserPool
 	SessionManager *user.SessionManager
 
 	ticker       *time.Ticker
 	quitChan     chan struct{}
+	stopOnce     sync.Once
+	stopping     atomic.Bool
+	exited       chan struct{} // 실행 루프와 Step 고루틴이 모두 끝나면 닫힘
 	tickInterval time.Duration
 
 	workerCount int
+
+	// 생성할 최대 이벤트 수 (0 이면 무제한)
+	maxEvents int64
+	issued    atomic.Int64
+	done      chan struct{}
+	doneOnce  sync.Once
+
+	// 주기적 체크포인트 (SetCheckpoint)
+	checkpointEvery time.Duration
+	checkpoint      func()
+
+	// sequential 모드에서 마지막으로 유저 풀을 확보한 가상 시각 (체크포인트에 저장)
+	lastEnsure time.Time
+	// sequential 모드에서 가상 시계가 이 시각에 도달하면 멈춤 (backfill, zero 면 무제한)
+	end time.Time
 }
 
+// workerCount: Step()을 호출할 고루틴 수
+// 2만 TPS 대응을 위해 CPU 코어 수의 2배 정도로 설정 권장 (예: 8코어 노트북이면 16개)
+// tickInterval: 10ms보다 20ms~50ms가 타이머 오차가 적고 안정적입니다.
+// clk: shape 의 현지 시각 / 경과 시간 기준 (seed 모드에서는 가상 시계)
 func NewLoadController(
-	tps int,
+	tps float64,
+	workerCount int,
+	tickInterval time.Duration,
 	up *user.UserPool,
 	sm *user.SessionManager,
+	clk clock.Clock,
 ) *LoadController {
-	// 2만 TPS 대응을 위해 워커 수를 CPU 코어 수의 2배 정도로 설정 권장
-	// 예: 8코어 노트북이면 16개
-	workerCount := 12
-
-	return &LoadController{
-		TargetTPS:      tps,
+	lc := &LoadController{
+		clock:          clk,
 		UserPool:       up,
 		SessionManager: sm,
 		quitChan:       make(chan struct{}),
-		tickInterval:   20 * time.Millisecond, // 10ms보다 20ms~50ms가 타이머 오차가 적고 안정적입니다.
+		exited:         make(chan struct{}),
+		tickInterval:   tickInterval,
 		workerCount:    workerCount,
+		done:           make(chan struct{}),
+	}
+	lc.baseTPS.Store(math.Float64bits(tps))
+	return lc
+}
+
+// activeShape : 적용 중인 shape 와 경과 시간 기준 시각
+type activeShape struct {
+	shape   *Shape
+	started time.Time
+}
+
+// SetShape : 매 tick 마다 shape 배수를 곱해 목표 TPS 를 계산하도록 설정합니다.
+// 실행 중에 호출하면 steps / after spike 의 경과 시간은 교체한 시점부터 다시 셉니다. nil 이면 shape 를 끕니다.
+func (lc *LoadController) SetShape(shape *Shape) {
+	if shape == nil {
+		lc.shape.Store(nil)
+		return
+	}
+	lc.shape.Store(&activeShape{shape: shape, started: lc.clock.Now()})
+}
+
+// ShapeEnabled : shape 적용 여부
+func (lc *LoadController) ShapeEnabled() bool {
+	return lc.shape.Load() != nil
+}
+
+// BaseTPS : shape 배수를 곱하기 전의 기본 목표 TPS
+func (lc *LoadController) BaseTPS() float64 {
+	return math.Float64frombits(lc.baseTPS.Load())
+}
+
+// SetTargetTPS : 기본 목표 TPS 를 바꿉니다. 다음 tick 부터 적용되며 진행 중인 세션은 그대로 유지됩니다.
+func (lc *LoadController) SetTargetTPS(tps float64) {
+	lc.baseTPS.Store(math.Float64bits(tps))
+	logging.Printf("[LoadController] target TPS set to %g\n", tps)
+}
+
+// Pause : Step 발급을 멈춥니다. 세션은 그대로 남아 Resume 후 이어서 진행합니다.
+func (lc *LoadController) Pause() {
+	if !lc.paused.Swap(true) {
+		logging.Println("[LoadController] paused")
+	}
+}
+
+// Resume : 일시 정지를 해제합니다.
+func (lc *LoadController) Resume() {
+	if lc.paused.Swap(false) {
+		logging.Println("[LoadController] resumed")
+	}
+}
+
+func (lc *LoadController) Paused() bool {
+	return lc.paused.Load()
+}
+
+// SetUserPoolSize : 유저 풀 크기를 n 명으로 고정합니다. (0 이면 목표 TPS / 활성 세션 수에 맞춰 자동)
+// 줄이면 앞의 n 명만 새 세션을 시작하며, 나머지 프로필은 지우지 않고 저장 대상으로 남겨 둡니다.
+func (lc *LoadController) SetUserPoolSize(n int) {
+	lc.userLimit.Store(int64(n))
+	lc.UserPool.SetLimit(n)
+	lc.UserPool.EnsureUsers(n)
+	logging.Printf("[LoadController] user pool size set to %d (0 = automatic)\n", n)
+}
+
+// UserPoolSize : 고정된 유저 풀 크기 (0 이면 자동)
+func (lc *LoadController) UserPoolSize() int {
+	return int(lc.userLimit.Load())
+}
+
+// CurrentTarget : 현재 tick 의 목표 TPS
+func (lc *LoadController) CurrentTarget() float64 {
+	if v := lc.current.Load(); v != 0 {
+		return math.Float64frombits(v)
+	}
+	return lc.BaseTPS()
+}
+
+// AchievedTPS : 직전 측정 구간(기본 1초, 낮은 TPS 에서는 더 길게)의 실제 초당 이벤트 수 (Start 모드에서 측정)
+func (lc *LoadController) AchievedTPS() float64 {
+	return lc.rate.Achieved()
+}
+
+// updateTarget : shape 를 평가해 현재 목표 TPS 를 갱신합니다.
+func (lc *LoadController) updateTarget() float64 {
+	target := lc.BaseTPS()
+	if as := lc.shape.Load(); as != nil {
+		now := lc.clock.Now()
+		target *= as.shape.Multiplier(now, now.Sub(as.started))
+	}
+	lc.current.Store(math.Float64bits(target))
+	return target
+}
+
+// SetMaxEvents : n 개의 이벤트를 생성하면 스스로 멈추도록 설정합니다. (Start 전에 호출)
+func (lc *LoadController) SetMaxEvents(n int64) {
+	lc.maxEvents = n
+}
+
+// SetEnd : RunSequential 이 가상 시계가 t 에 도달하면 스스로 멈추도록 설정합니다. (backfill, 시작 전에 호출)
+func (lc *LoadController) SetEnd(t time.Time) {
+	lc.end = t
+}
+
+// Done : maxEvents 만큼 생성을 마치거나 backfill 종료 시각에 도달하면 닫히는 채널
+func (lc *LoadController) Done() <-chan struct{} {
+	return lc.done
+}
+
+func (lc *LoadController) finish() {
+	lc.doneOnce.Do(func() { close(lc.done) })
+}
+
+// SetCheckpoint : 실제 시간 interval 마다 생성을 잠시 멈추고 진행 중인 Step 이 모두 끝난 시점에 fn 을 호출합니다. (Start 전에 호출)
+// fn 이 실행되는 동안에는 Step 이 호출되지 않으므로 유저 풀 / 세션 / 카운터를 한 시점의 상태로 저장할 수 있습니다.
+func (lc *LoadController) SetCheckpoint(interval time.Duration, fn func()) {
+	lc.checkpointEvery = interval
+	lc.checkpoint = fn
+}
+
+// LoadState : 체크포인트에 저장하는 LoadController 상태
+// 기본 목표 TPS / 일시 정지 / 유저 수 고정은 실행 중 조작이므로 저장하지 않고, 재개할 때는 설정 값을 사용합니다.
+type LoadState struct {
+	// 가상 시계의 나노초 단위까지 이어가야 시드 모드 출력이 같으므로 epoch millis 대신 시각으로 저장
+	ShapeStarted time.Time `json:"shape_started"` // shape 의 경과 시간 기준 시각 (shape 미사용이면 zero)
+	LastEnsure   time.Time `json:"last_ensure"`   // sequential 모드에서 마지막으로 유저 풀을 확보한 가상 시각
+}
+
+// State : 현재 상태 (체크포인트 콜백 안이나 실행 루프가 끝난 뒤에 호출)
+func (lc *LoadController) State() LoadState {
+	st := LoadState{LastEnsure: lc.lastEnsure}
+	if as := lc.shape.Load(); as != nil {
+		st.ShapeStarted = as.started
+	}
+	return st
+}
+
+// SetState : 체크포인트의 상태로 이어서 실행합니다. SetShape 이후, 실행 루프를 시작하기 전에 호출합니다.
+// steps / after spike 는 처음 시작한 시각 기준으로 이어서 진행합니다.
+func (lc *LoadController) SetState(st LoadState) {
+	if as := lc.shape.Load(); as != nil && !st.ShapeStarted.IsZero() {
+		lc.shape.Store(&activeShape{shape: as.shape, started: st.ShapeStarted})
 	}
+	lc.lastEnsure = st.LastEnsure
+}
+
+// reserve : maxEvents 한도 안에서 Step 1회(이벤트 1개 분량)를 예약합니다.
+func (lc *LoadController) reserve() bool {
+	if lc.maxEvents <= 0 {
+		return true
+	}
+	if lc.issued.Add(1) <= lc.maxEvents {
+		return true
+	}
+	lc.issued.Add(-1)
+	return false
+}
+
+// settle : Step 이 실제로 내보낸 이벤트 수 n 으로 예약과 발급 토큰을 정산합니다.
+// 내보내지 못했으면 예약 / 토큰을 돌려놓고, 생명주기 이벤트가 함께 나갔으면 그만큼 더 씁니다.
+// (한 Step 이 최대 3개를 내보내므로 -events 는 최대 2개까지 넘칠 수 있음)
+func (lc *LoadController) settle(n int) {
+	if n == 1 {
+		return
+	}
+	if lc.maxEvents > 0 {
+		lc.issued.Add(int64(n - 1))
+	}
+	lc.rate.charge(n - 1)
 }
 
 func (lc *LoadController) Start() {
@@ -43,54 +252,218 @@ func (lc *LoadController) Start() {
 	taskCh := make(chan int, lc.workerCount*2)
 
 	// 2. 워커 고루틴 풀 미리 생성 (딱 한 번만 실행됨)
+	// inflight 는 보냈지만 아직 처리가 끝나지 않은 배치 수 (체크포인트 전에 모두 끝나기를 기다림)
+	var tasks, inflight sync.WaitGroup
 	for w := 0; w < lc.workerCount; w++ {
+		tasks.Add(1)
 		go func(id int) {
+			defer tasks.Done()
 			for batchSize := range taskCh {
 				for i := 0; i < batchSize; i++ {
-					// 실제 이벤트 생성 로직 수행
-					lc.SessionManager.Step()
+					// 일시 정지 / 종료 중이면 이미 받은 배치도 버림 (밀린 발급이 정지 후에 쏟아지지 않도록)
+					if lc.paused.Load() || lc.stopping.Load() || !lc.reserve() {
+						break
+					}
+					// 실제 이벤트 생성 로직 수행 (내보낸 이벤트 수로 예약 정산)
+					lc.settle(lc.SessionManager.Step())
 				}
+				inflight.Done()
 			}
 		}(w)
 	}
+	// 체크포인트에서 이어서 실행하면 이전 실행의 생성 수부터 셈
+	lc.issued.Store(lc.SessionManager.Generated())
+	lastCheckpoint := time.Now()
 
 	lc.ticker = time.NewTicker(lc.tickInterval)
 	defer lc.ticker.Stop()
-	defer close(taskCh)
-
-	fmt.Printf("[LoadController] started (TargetTPS=%d, tick=%s, workers=%d)\n",
-		lc.TargetTPS, lc.tickInterval, lc.workerCount)
+	// 루프가 끝나면 Step 고루틴이 진행 중인 Step 을 마치고 종료할 때까지 기다린 뒤 exited 를 닫음
+	defer func() {
+		close(taskCh)
+		tasks.Wait()
+		close(lc.exited)
+	}()
 
-	ticksPerSecond := int(time.Second / lc.tickInterval)
-	totalBatchPerTick := lc.TargetTPS / ticksPerSecond
-	perWorkerBatch := totalBatchPerTick / lc.workerCount
+	logging.Printf("[LoadController] started (TargetTPS=%g, tick=%s, workers=%d)\n",
+		lc.BaseTPS(), lc.tickInterval, lc.workerCount)
 
-	if perWorkerBatch <= 0 {
-		perWorkerBatch = 1
-	}
+	lc.rate.tick(time.Now(), lc.updateTarget(), lc.SessionManager.Generated())
 
+	wasPaused := false
 	for {
 		select {
-		case <-lc.ticker.C:
+		case now := <-lc.ticker.C:
+			if lc.maxEvents > 0 && lc.SessionManager.Generated() >= lc.maxEvents {
+				logging.Printf("[LoadController] reached max events (%d)\n", lc.maxEvents)
+				lc.finish()
+				return
+			}
+
+			// 일시 정지 중에는 발급하지 않고, 재개하면 실측 / 보정을 처음부터 다시 시작
+			if lc.paused.Load() {
+				if !wasPaused {
+					wasPaused = true
+					lc.rate.achieved.Store(0)
+				}
+				continue
+			}
+			if wasPaused {
+				wasPaused = false
+				lc.rate.reset()
+				lc.rate.tick(now, lc.updateTarget(), lc.SessionManager.Generated())
+				continue
+			}
+
+			// 목표 TPS 갱신 (traffic shape)
+			target := lc.updateTarget()
+
 			// 유저 풀 확보
 			lc.UserPool.EnsureUsers(lc.requiredUserCount())
 
-			// 3. 고루틴 생성 없이 채널로 작업 지시만 내림 (매우 빠름)
-			for w := 0; w < lc.workerCount; w++ {
-				taskCh <- perWorkerBatch
+			// 3. 실측 기반으로 이번 tick 에 발급할 Step 수를 계산하고 워커에 나눠 줌
+			n := lc.rate.tick(now, target, lc.SessionManager.Generated())
+			if left := lc.dispatch(taskCh, &inflight, n); left > 0 {
+				lc.rate.giveBack(left)
 			}
 
+			// 4. 체크포인트 (보낸 배치가 모두 끝난 뒤 저장)
+			if lc.checkpoint != nil && lc.checkpointEvery > 0 && now.Sub(lastCheckpoint) >= lc.checkpointEvery {
+				inflight.Wait()
+				lc.checkpoint()
+				lastCheckpoint = time.Now()
+			}
+
+		case <-lc.quitChan:
+			logging.Println("[LoadController] stopping...")
+			return
+		}
+	}
+}
+
+// dispatch : n 개의 Step 을 워커 수만큼 나눠 채널로 보냅니다. 보낸 배치마다 inflight 에 더합니다.
+// 생산자가 밀려 채널이 가득 차면 기다리지 않고 보내지 못한 수를 반환합니다.
+func (lc *LoadController) dispatch(taskCh chan<- int, inflight *sync.WaitGroup, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	per, extra := n/lc.workerCount, n%lc.workerCount
+	for w := 0; w < lc.workerCount; w++ {
+		batch := per
+		if w < extra {
+			batch++
+		}
+		if batch == 0 {
+			break
+		}
+		inflight.Add(1)
+		select {
+		case taskCh <- batch:
+			n -= batch
+		default:
+			inflight.Done()
+			return n
+		}
+	}
+	return n
+}
+
+// sequential 모드에서 체크포인트 시각을 확인하는 Step 간격
+const checkpointCheckEvery = 1024
+
+// RunSequential : seed / backfill 모드용 실행 루프
+// 단일 고루틴에서 Step 을 순서대로 호출하고 이벤트 1개마다 가상 시계를 1/TargetTPS 만큼 진행시킵니다.
+// 실제 시간과 무관하게 Sink 가 받아들이는 속도로 최대한 빠르게 생성하며, SetEnd 로 정한 시각에 도달하면 멈춥니다.
+// 체크포인트에서 이어서 실행하면 (SetState) 유저 풀 확보 시각과 생성 수도 이어가므로 끊김 없이 같은 이벤트열이 나옵니다.
+func (lc *LoadController) RunSequential(clk *clock.Virtual) {
+	defer close(lc.exited)
+	if lc.lastEnsure.IsZero() {
+		lc.UserPool.EnsureUsers(lc.requiredUserCount())
+		lc.lastEnsure = clk.Now()
+	}
+
+	logging.Printf("[LoadController] sequential run started (TargetTPS=%g, virtual start=%s, maxEvents=%d)\n",
+		lc.BaseTPS(), clk.Now().Format(time.RFC3339), lc.maxEvents)
+
+	generated := lc.SessionManager.Generated()
+	lastCheckpoint := time.Now()
+	for i := 0; ; i++ {
+		select {
 		case <-lc.quitChan:
-			fmt.Println("[LoadController] stopping...")
+			logging.Println("[LoadController] stopping...")
+			return
+		default:
+		}
+
+		// 체크포인트 (Step 사이에 저장, 시계 확인 비용을 줄이기 위해 checkpointCheckEvery 번마다 확인)
+		if lc.checkpoint != nil && lc.checkpointEvery > 0 && i%checkpointCheckEvery == 0 && time.Since(lastCheckpoint) >= lc.checkpointEvery {
+			lc.checkpoint()
+			lastCheckpoint = time.Now()
+		}
+
+		if lc.maxEvents > 0 && generated >= lc.maxEvents {
+			logging.Printf("[LoadController] reached max events (%d)\n", lc.maxEvents)
+			lc.finish()
+			return
+		}
+		if !lc.end.IsZero() && !clk.Now().Before(lc.end) {
+			logging.Printf("[LoadController] reached end of simulated time (%s)\n", lc.end.Format(time.RFC3339))
+			lc.finish()
 			return
 		}
+
+		// 일시 정지 중에는 가상 시계도 멈춤
+		if lc.paused.Load() {
+			time.Sleep(lc.tickInterval)
+			continue
+		}
+
+		// 목표 TPS 는 traffic shape 에 따라 바뀌므로 간격을 매번 계산
+		// 목표가 0 이면 이벤트 없이 tick 만큼 시간을 흘려 보냄
+		target := lc.updateTarget()
+		if target <= 0 {
+			clk.Advance(lc.tickInterval)
+			continue
+		}
+		interval := time.Duration(float64(time.Second) / target)
+		clk.Advance(interval)
+		n := lc.SessionManager.Step()
+		generated += int64(n)
+		// session_start / session_end 가 함께 나가면 그만큼 시계를 더 진행해 가상 시간당 이벤트 수를 목표 TPS 에 맞춤
+		if n > 1 {
+			clk.Advance(time.Duration(n-1) * interval)
+		}
+
+		// 가상 시간 1초마다 유저 풀 확보
+		if now := clk.Now(); now.Sub(lc.lastEnsure) >= time.Second {
+			lc.lastEnsure = now
+			lc.UserPool.EnsureUsers(lc.requiredUserCount())
+		}
 	}
 }
 
-func (lc *LoadController) Stop() {
-	close(lc.quitChan)
+// Stop : 이벤트 생성을 멈추고 실행 루프와 진행 중인 Step 이 모두 끝날 때까지 기다립니다.
+// ctx 가 먼저 끝나면 기다리지 않고 오류를 반환합니다.
+func (lc *LoadController) Stop(ctx context.Context) error {
+	lc.stopOnce.Do(func() {
+		lc.stopping.Store(true)
+		close(lc.quitChan)
+	})
+	select {
+	case <-lc.exited:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("load controller stop: %w", ctx.Err())
+	}
 }
 
+// requiredUserCount : 세션은 체류 시간 동안 유저를 점유하므로
+// 활성 세션 수보다 넉넉하게 (새 세션을 시작할 쉬는 유저가 남도록) 유저를 확보합니다.
+// SetUserPoolSize 로 고정한 경우 그 값을 그대로 사용합니다.
 func (lc *LoadController) requiredUserCount() int {
-	return lc.TargetTPS * 2
+	if n := lc.userLimit.Load(); n > 0 {
+		return int(n)
+	}
+	active := lc.SessionManager.ActiveSessions()
+	target := int(math.Ceil(lc.CurrentTarget()))
+	return max(target*2, active+active/2+target)
 }
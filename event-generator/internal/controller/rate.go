@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// =======================
+// Rate control
+// =======================
+
+// 보정 계수: 1초마다 (목표 - 실측) 오차에 비례(kp) / 누적(ki) 해서 발급 속도를 보정합니다.
+const (
+	rateKp = 0.3
+	rateKi = 0.5
+
+	// 측정 구간 (이 시간이 지날 때마다 실측 TPS 를 계산)
+	// 낮은 TPS 에서는 구간당 이벤트가 몇 개뿐이라 정수 개수의 흔들림을 오차로 보정하지 않도록
+	// 구간 안에 최소 rateMinSamples 개가 들어오게 늘립니다. (최대 rateMaxWindow)
+	rateWindow     = time.Second
+	rateMaxWindow  = time.Minute
+	rateMinSamples = 20
+)
+
+// rateController : 토큰 버킷 + PI 보정
+//
+// 매 tick 마다 (목표 + 보정) × 경과 시간 만큼 토큰을 쌓고 정수 부분만 Step 으로 발급하므로
+// 소수 TPS 나 tick 당 1개 미만인 낮은 TPS 도 누적되어 정확히 맞춰집니다.
+// 토큰 하나는 이벤트 하나이며, Step 은 생명주기 이벤트를 함께 내보내 0~3개를 내보내므로
+// 발급한 1개와의 차이를 charge 로 버킷에서 정산합니다. (새 세션이 많은 낮은 TPS 에서 Step 당 이벤트가 1개를 크게 넘음)
+// 실측은 SessionManager 가 실제로 내보낸 이벤트 수로 하며, 남은 오차는 보정 값으로 조정합니다.
+type rateController struct {
+	tokens     float64
+	last       time.Time
+	correction float64 // 목표에 더하는 보정 TPS
+	integral   float64
+	owed       atomic.Int64 // Step 고루틴이 charge 한, 다음 tick 에 토큰에서 뺄 이벤트 수
+
+	windowStart time.Time
+	windowCount int64
+
+	achieved atomic.Uint64 // math.Float64bits(직전 구간 실측 TPS)
+}
+
+// tick : now 시점에 발급할 Step 수
+// generated 는 지금까지 내보낸 이벤트 누계입니다.
+func (rc *rateController) tick(now time.Time, target float64, generated int64) int {
+	if rc.last.IsZero() {
+		rc.last = now
+		rc.windowStart = now
+		rc.windowCount = generated
+		return 0
+	}
+
+	// 1. 실측 및 보정 (측정 구간마다)
+	if elapsed := now.Sub(rc.windowStart); elapsed >= measureWindow(target) {
+		achieved := float64(generated-rc.windowCount) / elapsed.Seconds()
+		rc.achieved.Store(math.Float64bits(achieved))
+		rc.windowStart = now
+		rc.windowCount = generated
+
+		err := target - achieved
+		// 생산 능력이 부족해 목표를 못 따라가는 동안 보정 값이 끝없이 커지지 않도록 제한 (anti-windup)
+		// 누적 오차(이벤트 수) 는 측정 구간 길이로 나눠 다음 구간 동안 나눠 갚음
+		// (낮은 TPS 의 긴 구간에서 이벤트 한두 개의 흔들림이 구간 내내 큰 보정으로 이어지지 않도록)
+		window := elapsed.Seconds()
+		rc.integral = clamp(rc.integral+err*window, -target*window, target*window)
+		rc.correction = clamp(rateKp*err+rateKi*rc.integral/window, -target/2, target)
+	}
+
+	// 2. 토큰 적립 (최대 1초 분량까지만 쌓아 밀린 발급이 한꺼번에 몰리지 않도록 함)
+	rate := max(target+rc.correction, 0)
+	rc.tokens = min(rc.tokens+rate*now.Sub(rc.last).Seconds(), max(rate, 1))
+	rc.tokens -= float64(rc.owed.Swap(0))
+	rc.last = now
+	if rc.tokens < 1 {
+		return 0
+	}
+
+	n := int(rc.tokens)
+	rc.tokens -= float64(n)
+	return n
+}
+
+// measureWindow : 목표 TPS 에서 최소 rateMinSamples 개가 들어오는 측정 구간
+func measureWindow(target float64) time.Duration {
+	if target <= 0 {
+		return rateWindow
+	}
+	w := time.Duration(rateMinSamples / target * float64(time.Second))
+	return min(max(w, rateWindow), rateMaxWindow)
+}
+
+// reset : 일시 정지 후 재개할 때 토큰 / 보정 / 측정 구간을 초기화합니다. (직전 실측 값은 유지)
+func (rc *rateController) reset() {
+	rc.tokens = 0
+	rc.last = time.Time{}
+	rc.correction = 0
+	rc.integral = 0
+	rc.owed.Store(0)
+}
+
+// giveBack : 발급하지 못한 Step 을 버킷에 돌려놓습니다.
+func (rc *rateController) giveBack(n int) {
+	rc.tokens += float64(n)
+}
+
+// charge : 발급한 Step 이 토큰 수보다 n 개 더 (음수면 덜) 내보냈음을 기록합니다. (Step 고루틴에서 호출)
+func (rc *rateController) charge(n int) {
+	if n != 0 {
+		rc.owed.Add(int64(n))
+	}
+}
+
+// Achieved : 직전 측정 구간의 실측 TPS
+func (rc *rateController) Achieved() float64 {
+	return math.Float64frombits(rc.achieved.Load())
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return min(max(v, lo), hi)
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestRateControllerLowTPS : Step 이 이벤트를 1개보다 많이 내보내도 (새 세션의 session_start + 행동 이벤트 등)
+// 낮은 / 소수 목표 TPS 에서 내보낸 이벤트 수가 목표에 맞는지 확인합니다.
+// 생성 수는 가짜 카운터로 세며, Step 은 perStep 의 값을 차례로 내보냅니다.
+func TestRateControllerLowTPS(t *testing.T) {
+	const (
+		tick     = 10 * time.Millisecond
+		duration = 10 * time.Minute
+	)
+	cases := []struct {
+		target  float64
+		perStep []int
+	}{
+		{5, []int{2}},          // 낮은 TPS: 거의 모든 Step 이 새 세션 (session_start + 행동)
+		{5, []int{2, 1, 3, 1}}, // 생명주기 이벤트가 섞임
+		{0.5, []int{2, 1}},     // tick 당 1개 미만
+		{2.5, []int{1, 0, 2}},  // 내보내지 못한 Step 포함
+		{1000, []int{1, 2, 1}},
+	}
+	for _, c := range cases {
+		var (
+			rc        rateController
+			generated int64
+			steps     int
+		)
+		start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		for now := start; now.Sub(start) <= duration; now = now.Add(tick) {
+			n := rc.tick(now, c.target, generated)
+			for range n {
+				emitted := c.perStep[steps%len(c.perStep)]
+				steps++
+				generated += int64(emitted)
+				rc.charge(emitted - 1)
+			}
+		}
+
+		got := float64(generated) / duration.Seconds()
+		if math.Abs(got-c.target)/c.target > 0.02 {
+			t.Errorf("target %g TPS with %v events per step: achieved %.3f TPS (%d events in %s)",
+				c.target, c.perStep, got, generated, duration)
+		}
+		if a := rc.Achieved(); math.Abs(a-c.target)/c.target > 0.25 {
+			t.Errorf("target %g TPS with %v events per step: last measured window %.3f TPS", c.target, c.perStep, a)
+		}
+	}
+}
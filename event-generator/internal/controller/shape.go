@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	_ "time/tzdata" // 컨테이너에 tzdata 가 없어도 timezone 을 읽을 수 있도록 포함
+)
+
+// =======================
+// Load shape
+// =======================
+
+// Shape : 시간에 따라 목표 TPS 에 곱할 배수를 계산합니다.
+//
+//	target(t) = TargetTPS × daily(t) × weekly(t) × steps(경과) × spikes(t)
+//
+// daily / weekly / 매일 반복 spike 는 Location 기준 현지 시각으로, steps / After spike 는 실행 시작 후 경과 시간으로 계산합니다.
+type Shape struct {
+	Location *time.Location
+	Daily    *Daily
+	Weekly   map[time.Weekday]float64 // 요일별 배수 (없는 요일은 1)
+	Steps    []Step                   // After 오름차순
+	Spikes   []Spike
+}
+
+// Daily : 하루 주기 사인 곡선 (1 ± Amplitude, PeakHour 에 최대)
+type Daily struct {
+	Amplitude float64
+	PeakHour  float64 // 0~24 현지 시각
+}
+
+// Step : 실행 시작 After 후부터 Multiplier 로 바꾸며, Ramp 동안 이전 배수에서 선형으로 변화합니다.
+type Step struct {
+	After      time.Duration
+	Multiplier float64
+	Ramp       time.Duration
+}
+
+// Spike : Duration 동안 Multiplier 배로 트래픽이 몰리는 구간 (플래시 세일)
+// Daily 가 false 면 실행 시작 After 후에 한 번, true 면 매일 현지 시각 DailyAt 에 반복합니다.
+// Ramp 는 시작과 끝에서 배수가 선형으로 오르내리는 시간입니다.
+type Spike struct {
+	After      time.Duration
+	DailyAt    time.Duration // 자정부터의 시각 (예: 20:00 → 20h)
+	Daily      bool
+	Duration   time.Duration
+	Multiplier float64
+	Ramp       time.Duration
+}
+
+// Validate : 배수 / 구간 값 검사
+func (s *Shape) Validate() error {
+	var errs []error
+
+	if d := s.Daily; d != nil {
+		if d.Amplitude < 0 || d.Amplitude > 1 {
+			errs = append(errs, fmt.Errorf("daily.amplitude must be in [0, 1] (got %g)", d.Amplitude))
+		}
+		if d.PeakHour < 0 || d.PeakHour >= 24 {
+			errs = append(errs, fmt.Errorf("daily.peak_hour must be in [0, 24) (got %g)", d.PeakHour))
+		}
+	}
+	for day, m := range s.Weekly {
+		if m < 0 {
+			errs = append(errs, fmt.Errorf("weekly.%s must be >= 0 (got %g)", strings.ToLower(day.String()[:3]), m))
+		}
+	}
+	for i, st := range s.Steps {
+		if st.After < 0 || st.Ramp < 0 || st.Multiplier < 0 {
+			errs = append(errs, fmt.Errorf("steps[%d]: after, ramp and multiplier must be >= 0", i))
+		}
+		if i > 0 && st.After < s.Steps[i-1].After {
+			errs = append(errs, fmt.Errorf("steps[%d]: after must not be earlier than steps[%d]", i, i-1))
+		}
+	}
+	for i, sp := range s.Spikes {
+		if sp.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("spikes[%d]: duration must be > 0", i))
+		}
+		if sp.Multiplier < 0 || sp.Ramp < 0 || sp.After < 0 {
+			errs = append(errs, fmt.Errorf("spikes[%d]: after, ramp and multiplier must be >= 0", i))
+		}
+		if 2*sp.Ramp > sp.Duration {
+			errs = append(errs, fmt.Errorf("spikes[%d]: ramp must be at most half of duration", i))
+		}
+		if sp.Daily && (sp.DailyAt < 0 || sp.DailyAt >= 24*time.Hour) {
+			errs = append(errs, fmt.Errorf("spikes[%d]: daily_at must be within a day", i))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Multiplier : now 시각, 실행 시작 후 elapsed 경과 시점의 배수
+func (s *Shape) Multiplier(now time.Time, elapsed time.Duration) float64 {
+	if s == nil {
+		return 1
+	}
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	m := 1.0
+
+	// 1. 하루 주기
+	if d := s.Daily; d != nil {
+		hour := float64(local.Hour()) + float64(local.Minute())/60 + float64(local.Second())/3600
+		m *= 1 + d.Amplitude*math.Cos(2*math.Pi*(hour-d.PeakHour)/24)
+	}
+
+	// 2. 요일
+	if w, ok := s.Weekly[local.Weekday()]; ok {
+		m *= w
+	}
+
+	// 3. 단계 / 램프
+	m *= s.stepMultiplier(elapsed)
+
+	// 4. 스파이크 (겹치면 모두 곱함)
+	// 벽시계 기준 시각 (DST 전환일은 자정부터의 실제 경과 시간이 23/25시간이라 local.Sub(자정) 을 쓰면 한 시간 어긋남)
+	timeOfDay := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second + time.Duration(local.Nanosecond())
+	for _, sp := range s.Spikes {
+		var since time.Duration
+		if sp.Daily {
+			since = timeOfDay - sp.DailyAt
+			if since < 0 {
+				// 전날 시작해서 자정을 넘긴 스파이크
+				since += 24 * time.Hour
+			}
+		} else {
+			since = elapsed - sp.After
+		}
+		m *= sp.multiplier(since)
+	}
+
+	return m
+}
+
+func (s *Shape) stepMultiplier(elapsed time.Duration) float64 {
+	m := 1.0
+	for _, st := range s.Steps {
+		if elapsed < st.After {
+			break
+		}
+		prev := m
+		m = st.Multiplier
+		if into := elapsed - st.After; into < st.Ramp {
+			m = prev + (st.Multiplier-prev)*float64(into)/float64(st.Ramp)
+		}
+	}
+	return m
+}
+
+// multiplier : 스파이크 시작 후 since 시점의 배수 (구간 밖이면 1)
+func (sp *Spike) multiplier(since time.Duration) float64 {
+	if since < 0 || since >= sp.Duration {
+		return 1
+	}
+	level := 1.0
+	switch {
+	case sp.Ramp > 0 && since < sp.Ramp:
+		level = float64(since) / float64(sp.Ramp)
+	case sp.Ramp > 0 && since > sp.Duration-sp.Ramp:
+		level = float64(sp.Duration-since) / float64(sp.Ramp)
+	}
+	return 1 + (sp.Multiplier-1)*level
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDailySpikeOnDSTChange : DST 전환일에도 매일 스파이크가 벽시계 시각에 맞춰 켜지는지 확인합니다.
+func TestDailySpikeOnDSTChange(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	s := &Shape{
+		Location: loc,
+		Spikes:   []Spike{{Daily: true, DailyAt: 20 * time.Hour, Duration: time.Hour, Multiplier: 3}},
+	}
+
+	// 2025-03-09 (23시간), 2025-11-02 (25시간) 전환일과 평일 비교
+	for _, day := range []time.Time{
+		time.Date(2025, 3, 8, 0, 0, 0, 0, loc),
+		time.Date(2025, 3, 9, 0, 0, 0, 0, loc),
+		time.Date(2025, 11, 2, 0, 0, 0, 0, loc),
+	} {
+		cases := []struct {
+			hour, min int
+			want      float64
+		}{
+			{19, 30, 1},
+			{20, 0, 3},
+			{20, 30, 3},
+			{21, 0, 1},
+		}
+		for _, c := range cases {
+			now := time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.min, 0, 0, loc)
+			if got := s.Multiplier(now, 0); got != c.want {
+				t.Errorf("%s: multiplier %v, want %v", now.Format(time.RFC3339), got, c.want)
+			}
+		}
+	}
+}
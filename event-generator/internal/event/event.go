@@ -1,12 +1,31 @@
 package event
 
+// SchemaVersion : 현재 이벤트 스키마 버전
+// 필드를 추가/변경하면 올리고 schemas/ 의 스키마 파일을 재생성합니다. (go run ./cmd/schemagen)
+// 새 필드는 Protobuf 필드 번호가 바뀌지 않도록 항상 구조체 맨 뒤에 추가해야 합니다.
+const SchemaVersion = 5
+
+// 세션 생명주기 이벤트 (FSM 전이가 아니라 SessionManager 가 세션 시작 / 종료 시 내보냄)
+const (
+	TypeSessionStart = "session_start"
+	TypeSessionEnd   = "session_end"
+)
+
+// session_end 의 종료 사유
+const (
+	EndReasonExit         = "exit"          // 구매 없이 종료 상태에 도달
+	EndReasonPurchaseExit = "purchase_exit" // 구매 후 종료 상태에 도달
+	EndReasonTimeout      = "timeout"       // 다음 행동 없이 TTL 이 지남
+)
+
 type Event struct {
-	EventID    string          `json:"event_id"`
-	EventType  string          `json:"event_type"`
-	EventTs    int64           `json:"event_ts"` // epoch millis
-	UserID     string          `json:"user_id"`
-	SessionID  string          `json:"session_id"`
-	Attributes EventAttributes `json:"attributes"` // 유저 행동 구체 정보
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	EventTs       int64           `json:"event_ts"` // epoch millis
+	UserID        string          `json:"user_id"`
+	SessionID     string          `json:"session_id"`
+	Attributes    EventAttributes `json:"attributes"` // 유저 행동 구체 정보
+	SchemaVersion int             `json:"schema_version"`
 }
 
 type EventAttributes struct {
@@ -17,7 +36,27 @@ type EventAttributes struct {
 	Product   *ProductInfo   `json:"product,omitempty"`
 	Device    string         `json:"device,omitempty"`
 	Referrer  string         `json:"referrer,omitempty"`
-	Extra     map[string]any `json:"extra,omitempty"`
+	Extra     map[string]any `json:"extra,omitempty"`   // 이벤트별 payload (purchased 의 주문 정보는 generator.checkout 참고)
+	Items     []ProductInfo  `json:"items,omitempty"`   // 장바구니/주문 상품 목록 (스키마 v3 에서 추가)
+	User      *UserInfo      `json:"user,omitempty"`    // 유저 프로필 (스키마 v4 에서 추가)
+	Session   *SessionInfo   `json:"session,omitempty"` // 세션 생명주기 정보 (스키마 v5 에서 추가)
+}
+
+// SessionInfo : session_start / session_end 이벤트에만 채워지는 세션 요약
+type SessionInfo struct {
+	EntryPage  string `json:"entry_page,omitempty"`  // 세션의 첫 화면
+	EndReason  string `json:"end_reason,omitempty"`  // session_end: exit | purchase_exit | timeout
+	DurationMs int64  `json:"duration_ms,omitempty"` // session_end: 첫 이벤트부터 종료까지 (epoch millis 차이)
+	EventCount int    `json:"event_count,omitempty"` // session_end: 세션 중 행동 이벤트 수 (생명주기 이벤트 제외)
+}
+
+// UserInfo : 이벤트 시점의 유저 프로필 요약 (재방문 / 코호트 분석용)
+type UserInfo struct {
+	HomeCountry   string `json:"home_country,omitempty"`
+	Language      string `json:"language,omitempty"`
+	LoyaltyTier   string `json:"loyalty_tier,omitempty"`
+	SignupDate    string `json:"signup_date,omitempty"`    // YYYY-MM-DD
+	SessionNumber int    `json:"session_number,omitempty"` // 유저의 몇 번째 세션인지 (1 이면 첫 방문)
 }
 
 type ProductInfo struct {
@@ -25,5 +64,7 @@ type ProductInfo struct {
 	Category   string `json:"category,omitempty"`
 	VendorType string `json:"vendor_type,omitempty"`
 	ProductID  string `json:"product_id,omitempty"`
-	Price      int    `json:"price,omitempty"`
+	Price      int    `json:"price,omitempty"`    // 판매처 판매가 (Currency 기준)
+	Currency   string `json:"currency,omitempty"` // 스키마 v2 에서 추가
+	Quantity   int    `json:"quantity,omitempty"` // 스키마 v3 에서 추가
 }
@@ -0,0 +1,18 @@
+package fsm
+
+// CartLine : 장바구니 한 줄 (상품 + 판매처 + 수량)
+// 같은 상품이라도 판매처가 다르면 다른 줄입니다.
+type CartLine struct {
+	ProductID string
+	Category  string
+	Country   string
+	Vendor    string
+	Quantity  int
+	UnitPrice int    // 판매처 판매가
+	Currency  string // 상품 국가 통화
+}
+
+// Subtotal : 줄 금액
+func (l CartLine) Subtotal() int {
+	return l.UnitPrice * l.Quantity
+}
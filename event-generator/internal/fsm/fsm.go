@@ -3,7 +3,8 @@ package fsm
 import (
 	"event-generator/internal/event"
 	"fmt"
-	"math/rand/v2" // v1 대신 v2를 사용합니다. (Thread-safe 최적화)
+	"math/rand/v2"
+	"sync/atomic"
 )
 
 // =======================================================
@@ -19,6 +20,10 @@ type Session interface {
 	GetPrevState() State
 	SetPrevState(State)
 
+	// back 이벤트로 돌아갈 화면 (같은 화면에 머무는 전이에서는 바뀌지 않음)
+	GetBackState() State
+	SetBackState(State)
+
 	GetLastEventTs() int64
 	SetLastEventTs(int64)
 
@@ -40,15 +45,37 @@ type Session interface {
 	GetLastCategory() string
 	GetLastCountry() string
 	GetLastPicked() (productID, category, country string)
+	SetLastVendor(vendor string)
+	GetLastVendor() string
 
 	SetLastQuantity(qty int)
 	GetLastQuantity() int
 
+	// 장바구니 (여러 상품) 와 마지막으로 결제한 주문 줄
+	GetCart() []CartLine
+	SetCart([]CartLine)
+	GetLastOrder() []CartLine
+	SetLastOrder([]CartLine)
+
 	GetPageIndex() int
 	SetPageIndex(int)
 	IncrementPageIndex()
 
 	SetExpiresAt(int64)
+
+	// 유저 단위로 고정된 접속 기기 / 세션 단위 유입 경로
+	GetDevice() string
+	GetReferrer() string
+	SetReferrer(string)
+
+	// 유저 프로필: 선호 여행지 / 가격 민감도 (0~1)
+	GetPreferredDestinations() []string
+	GetPriceSensitivity() float64
+	// 프로필/페르소나에 따라 from 상태의 이벤트 전이 가중치에 곱할 배수 (1 이면 모델 가중치 그대로)
+	EventBias(from State, ev EventType) float64
+
+	// 세션 전용 난수 스트림 (한 세션의 이벤트는 순차적으로 생성되므로 락 없이 사용)
+	Rand() *rand.Rand
 }
 
 // =======================================================
@@ -56,39 +83,76 @@ type Session interface {
 // =======================================================
 type FSM interface {
 	Step(s Session, now int64) *event.Event
+
+	// 새 세션의 시작 상태
+	InitialState() State
+	// 도달하면 세션이 끝나는 상태인지 여부
+	IsTerminal(State) bool
 }
 
 // =======================================================
 // SimpleFSM
 // =======================================================
+// 난수는 세션별 스트림(Session.Rand)을 사용합니다.
+// 모델은 SetModel 로 실행 중에 교체할 수 있으며, Step 은 호출 시점의 모델 하나만 사용합니다.
 type SimpleFSM struct {
-	// 이제 rnd *rand.Rand를 저장할 필요가 없습니다.
-	// rand/v2의 전역 함수는 내부적으로 성능 최적화와 동시성 보호가 되어 있습니다.
+	model atomic.Pointer[Model]
+}
+
+// NewSimpleFSM : model 이 nil 이면 기본 그래프(DefaultModel)를 사용합니다.
+func NewSimpleFSM(model *Model) *SimpleFSM {
+	if model == nil {
+		model = DefaultModel()
+	}
+	f := &SimpleFSM{}
+	f.model.Store(model)
+	return f
+}
+
+// Model : 현재 적용 중인 모델
+func (f *SimpleFSM) Model() *Model {
+	return f.model.Load()
+}
+
+// SetModel : 모델을 교체합니다. (nil 이면 DefaultModel)
+// 진행 중인 세션은 다음 Step 부터 새 모델을 따르며, 새 모델에 없는 상태에 있던 세션은 종료됩니다.
+func (f *SimpleFSM) SetModel(model *Model) {
+	if model == nil {
+		model = DefaultModel()
+	}
+	f.model.Store(model)
+}
+
+func (f *SimpleFSM) InitialState() State {
+	return f.model.Load().Initial
 }
 
-func NewSimpleFSM() *SimpleFSM {
-	return &SimpleFSM{}
+func (f *SimpleFSM) IsTerminal(s State) bool {
+	return f.model.Load().IsTerminal(s)
 }
 
 // =======================================================
 // Step
 // =======================================================
 func (f *SimpleFSM) Step(s Session, now int64) *event.Event {
+	model := f.model.Load()
 
-	// 1. terminal state 처리
-	transitions, ok := Transitions[s.GetState()]
-	if !ok || len(transitions) == 0 {
-		return nil
+	// 1. 최초 상태가 없다면 모델의 초기 상태로 설정
+	if s.GetState() == StateNone {
+		s.SetState(model.Initial)
 	}
 
-	// 2. 최초 상태가 없다면 StateBrowsing으로 설정
-	if s.GetState() == StateNone {
-		s.SetState(StateBrowsing)
+	// 2. terminal state 처리
+	transitions, ok := model.Transitions[s.GetState()]
+	if !ok || len(transitions) == 0 {
+		return nil
 	}
 
 	// 3. transition 선택
-	// f.rnd 대신 전역 rand를 사용하도록 chooseTransition의 인자를 수정해야 합니다.
-	tr := chooseTransition(transitions)
+	from := s.GetState()
+	tr := chooseTransition(s.Rand(), transitions, func(ev EventType) float64 {
+		return s.EventBias(from, ev)
+	})
 	if tr == nil {
 		return nil
 	}
@@ -99,30 +163,34 @@ func (f *SimpleFSM) Step(s Session, now int64) *event.Event {
 	evType := tr.Event
 
 	// 5. Back 이벤트 처리
+	// 돌아갈 화면이 없으면 (뒤로 갈 곳이 기록되기 전에 저장된 세션) 직전 상태, 그것도 없으면 초기 상태
 	if evType == EventBack {
-		if s.GetPrevState() != StateNone {
+		nextState = s.GetBackState()
+		if nextState == StateNone {
 			nextState = s.GetPrevState()
-		} else {
-			nextState = StateBrowsing
+		}
+		if nextState == StateNone {
+			nextState = model.Initial
 		}
 	}
 
 	// 6. 세션 상태 갱신
+	// 같은 화면에 머무는 전이 (장바구니 수량 변경, 홈 / 검색 결과 스크롤 등) 는 뒤로가기 대상을 바꾸지 않음
+	if nextState != prevState {
+		s.SetBackState(prevState)
+	}
 	s.SetPrevState(prevState)
 	s.SetState(nextState)
 	s.SetLastEventTs(now)
 
-	// 7. 검색 이벤트일 때만 키워드 생성
+	// 7. 검색 이벤트면 페이지 인덱스 초기화 (검색어는 PayloadGenerator 가 카탈로그에서 선택)
 	if evType == EventSearchSubmitted {
-		// FakeKeyword 역시 내부적으로 rand/v2를 쓰도록 수정 필요합니다.
-		s.SetSearchKeyword(FakeKeyword())
 		s.SetPageIndex(1)
 	}
 
 	// 8. 이벤트 생성
-	// rand.Int63n -> rand.Int64N (v2 대문자 N)으로 변경하여 안전하게 ID 생성
 	return &event.Event{
-		EventID:   fmt.Sprintf("evt-%d-%09d", now, rand.Int64N(1_000_000_000)),
+		EventID:   fmt.Sprintf("evt-%d-%09d", now, s.Rand().Int64N(1_000_000_000)),
 		EventType: string(evType),
 		EventTs:   now,
 		UserID:    s.GetUserID(),
@@ -131,5 +199,6 @@ func (f *SimpleFSM) Step(s Session, now int64) *event.Event {
 			State:     string(s.GetState()),
 			PrevState: string(prevState),
 		},
+		SchemaVersion: event.SchemaVersion,
 	}
 }
@@ -0,0 +1,69 @@
+package fsm_test
+
+import (
+	"testing"
+	"time"
+
+	"event-generator/internal/fsm"
+	"event-generator/internal/rng"
+	"event-generator/internal/user"
+)
+
+// TestBackSkipsSelfLoops : 장바구니 수량 변경 / 빼기처럼 같은 화면에 머무는 전이 뒤의 back 이
+// 장바구니로 들어오기 전 화면으로 돌아가는지 확인합니다.
+func TestBackSkipsSelfLoops(t *testing.T) {
+	cases := []struct {
+		name string
+		path []fsm.Transition // 차례로 강제할 전이
+		want fsm.State        // 마지막 back 의 도착 상태
+	}{
+		{
+			name: "browsing cart",
+			path: []fsm.Transition{
+				{Event: fsm.EventCartViewed, NextState: fsm.StateCart},
+				{Event: fsm.EventUpdateQuantity, NextState: fsm.StateCart},
+				{Event: fsm.EventBack},
+			},
+			want: fsm.StateBrowsing,
+		},
+		{
+			name: "add to cart then cart",
+			path: []fsm.Transition{
+				{Event: fsm.EventProductClicked, NextState: fsm.StateClick},
+				{Event: fsm.EventAddToCart, NextState: fsm.StateAddToCart},
+				{Event: fsm.EventCartViewed, NextState: fsm.StateCart},
+				{Event: fsm.EventUpdateQuantity, NextState: fsm.StateCart},
+				{Event: fsm.EventRemoveFromCart, NextState: fsm.StateCart},
+				{Event: fsm.EventBack},
+			},
+			want: fsm.StateAddToCart,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := user.NewSession("sess", "user", 0, time.Minute, rng.NewFactory(1).Source("session"))
+			f := fsm.NewSimpleFSM(nil)
+			for i, tr := range c.path {
+				// 현재 상태에서 이 전이만 가능한 모델로 바꿔 경로를 강제
+				from := s.GetState()
+				if from == fsm.StateNone {
+					from = fsm.StateBrowsing
+				}
+				tr.Weight = 1
+				f.SetModel(&fsm.Model{Initial: fsm.StateBrowsing, Transitions: map[fsm.State][]fsm.Transition{from: {tr}}})
+
+				ev := f.Step(s, int64(i))
+				if ev == nil || ev.EventType != string(tr.Event) {
+					t.Fatalf("step %d: got %+v, want %s from %s", i, ev, tr.Event, from)
+				}
+				if ev.Attributes.PrevState != string(from) {
+					t.Fatalf("step %d: prev_state %s, want %s", i, ev.Attributes.PrevState, from)
+				}
+			}
+			if got := s.GetState(); got != c.want {
+				t.Fatalf("back went to %s, want %s", got, c.want)
+			}
+		})
+	}
+}
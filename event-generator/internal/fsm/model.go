@@ -0,0 +1,263 @@
+package fsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =======================================================
+// Model (상태 전이 그래프)
+// =======================================================
+
+// Model 은 FSM 이 사용하는 상태/이벤트/전이 가중치 그래프입니다.
+// 파일(YAML/JSON)에서 불러오거나 DefaultModel 로 기본 그래프를 사용합니다.
+type Model struct {
+	Initial     State                  `json:"initial" yaml:"initial"`
+	States      []State                `json:"states" yaml:"states"`
+	Terminal    []State                `json:"terminal" yaml:"terminal"`
+	Events      []EventType            `json:"events,omitempty" yaml:"events,omitempty"`
+	Transitions map[State][]Transition `json:"transitions" yaml:"transitions"`
+
+	terminal map[State]bool
+}
+
+// 가중치 합이 1에서 이 이상 벗어나면 경고
+const weightSumTolerance = 1e-6
+
+// DefaultModel : 코드에 정의된 기본 그래프(Transitions)
+func DefaultModel() *Model {
+	m := &Model{
+		Initial: StateBrowsing,
+		States: []State{
+			StateBrowsing, StateEventBrowsing, StateSearch, StateNextPage,
+			StateClick, StateAddToCart, StateCart, StatePurchase, StateExit,
+		},
+		Terminal: []State{StateExit},
+		Events: []EventType{
+			EventSearchSubmitted, EventPageViewed, EventPageClicked, EventProductClicked,
+			EventCategoryClicked, EventAddToCart, EventPurchased,
+			EventCartViewed, EventRemoveFromCart, EventUpdateQuantity, EventBack, EventExit,
+		},
+		Transitions: Transitions,
+	}
+	m.index()
+	return m
+}
+
+// LoadModel : YAML/JSON 모델 파일을 읽고 검증합니다.
+// 치명적이지 않은 문제(가중치 합이 1이 아님 등)는 warnings 로 반환합니다.
+func LoadModel(path string) (m *Model, warnings []string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read fsm model %s: %w", path, err)
+	}
+
+	m = &Model{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(m)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(m)
+	default:
+		return nil, nil, fmt.Errorf("unsupported fsm model format: %s (use .yaml, .yml or .json)", path)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse fsm model %s: %w", path, err)
+	}
+
+	warnings, err = m.Validate()
+	if err != nil {
+		return nil, warnings, fmt.Errorf("invalid fsm model %s:\n%w", path, err)
+	}
+	return m, warnings, nil
+}
+
+func (m *Model) index() {
+	m.terminal = make(map[State]bool, len(m.Terminal))
+	for _, s := range m.Terminal {
+		m.terminal[s] = true
+	}
+}
+
+// IsTerminal : 종료 상태 여부 (도달하면 세션이 끝남)
+func (m *Model) IsTerminal(s State) bool {
+	return m.terminal[s]
+}
+
+// =======================================================
+// Validation
+// =======================================================
+
+// Validate : 그래프 정합성을 검사합니다.
+//   - 선언되지 않은 상태/이벤트 참조
+//   - 초기 상태에서 도달할 수 없는 상태
+//   - 음수/NaN 가중치, 가중치 합이 0 인 상태 (합이 1 이 아니면 경고)
+//   - 나가는 전이가 있는 종료 상태, 전이가 없는 비종료 상태
+//   - 종료 상태로 갈 수 없는 상태 (Exit 경로 누락)
+func (m *Model) Validate() (warnings []string, err error) {
+	var errs []error
+
+	states := make(map[State]bool, len(m.States))
+	for _, s := range m.States {
+		if s == StateNone {
+			errs = append(errs, errors.New("states: empty state name"))
+			continue
+		}
+		if states[s] {
+			errs = append(errs, fmt.Errorf("states: duplicate state %q", s))
+		}
+		states[s] = true
+	}
+	if len(states) == 0 {
+		return nil, errors.New("states: at least one state is required")
+	}
+
+	var events map[EventType]bool
+	if len(m.Events) > 0 {
+		events = make(map[EventType]bool, len(m.Events))
+		for _, e := range m.Events {
+			events[e] = true
+		}
+	}
+
+	if !states[m.Initial] {
+		errs = append(errs, fmt.Errorf("initial: unknown state %q", m.Initial))
+	}
+	if len(m.Terminal) == 0 {
+		errs = append(errs, errors.New("terminal: at least one terminal state is required"))
+	}
+	for _, s := range m.Terminal {
+		if !states[s] {
+			errs = append(errs, fmt.Errorf("terminal: unknown state %q", s))
+		}
+	}
+	m.index()
+
+	for _, from := range sortedStates(m.Transitions) {
+		ts := m.Transitions[from]
+		if !states[from] {
+			errs = append(errs, fmt.Errorf("transitions: unknown source state %q", from))
+			continue
+		}
+		if m.terminal[from] {
+			if len(ts) > 0 {
+				errs = append(errs, fmt.Errorf("transitions: terminal state %q has %d outgoing transitions", from, len(ts)))
+			}
+			continue
+		}
+
+		total := 0.0
+		for i, t := range ts {
+			if t.Event == "" {
+				errs = append(errs, fmt.Errorf("transitions[%s][%d]: empty event", from, i))
+			} else if events != nil && !events[t.Event] {
+				errs = append(errs, fmt.Errorf("transitions[%s][%d]: unknown event %q", from, i, t.Event))
+			}
+			// back 은 Step 에서 이전 상태로 되돌아가므로 next 를 비워 둘 수 있습니다.
+			if t.Event != EventBack && !states[t.NextState] {
+				errs = append(errs, fmt.Errorf("transitions[%s][%d]: unknown next state %q", from, i, t.NextState))
+			}
+			if math.IsNaN(t.Weight) || math.IsInf(t.Weight, 0) || t.Weight < 0 {
+				errs = append(errs, fmt.Errorf("transitions[%s][%d]: invalid weight %v", from, i, t.Weight))
+				continue
+			}
+			total += t.Weight
+		}
+		if total <= 0 {
+			errs = append(errs, fmt.Errorf("transitions[%s]: weights sum to %v", from, total))
+		} else if math.Abs(total-1) > weightSumTolerance {
+			warnings = append(warnings, fmt.Sprintf("transitions[%s]: weights sum to %.4f (normalized at runtime)", from, total))
+		}
+	}
+
+	for _, s := range m.States {
+		if !m.terminal[s] && len(m.Transitions[s]) == 0 {
+			errs = append(errs, fmt.Errorf("state %q is not terminal but has no transitions", s))
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, errors.Join(errs...)
+	}
+
+	// 그래프 구조가 유효할 때만 도달성 검사
+	reachable := m.reachableFrom(m.Initial)
+	for _, s := range m.States {
+		if !reachable[s] {
+			errs = append(errs, fmt.Errorf("state %q is unreachable from initial state %q", s, m.Initial))
+		}
+	}
+	canExit := m.canReachTerminal()
+	for _, s := range m.States {
+		if reachable[s] && !canExit[s] {
+			errs = append(errs, fmt.Errorf("state %q has no path to a terminal state", s))
+		}
+	}
+
+	return warnings, errors.Join(errs...)
+}
+
+// reachableFrom : start 에서 가중치 > 0 인 전이로 도달 가능한 상태
+// back 전이는 이미 방문한 상태로 돌아가는 것이므로 새 상태를 추가하지 않습니다.
+func (m *Model) reachableFrom(start State) map[State]bool {
+	seen := map[State]bool{start: true}
+	queue := []State{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, t := range m.Transitions[cur] {
+			if t.Event == EventBack || t.Weight <= 0 || seen[t.NextState] {
+				continue
+			}
+			seen[t.NextState] = true
+			queue = append(queue, t.NextState)
+		}
+	}
+	return seen
+}
+
+// canReachTerminal : 종료 상태까지 가는 경로가 있는 상태 (역방향 고정점 계산)
+func (m *Model) canReachTerminal() map[State]bool {
+	ok := make(map[State]bool, len(m.States))
+	for s := range m.terminal {
+		ok[s] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, s := range m.States {
+			if ok[s] {
+				continue
+			}
+			for _, t := range m.Transitions[s] {
+				if t.Event != EventBack && t.Weight > 0 && ok[t.NextState] {
+					ok[s] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return ok
+}
+
+func sortedStates(ts map[State][]Transition) []State {
+	out := make([]State, 0, len(ts))
+	for s := range ts {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
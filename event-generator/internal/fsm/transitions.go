@@ -1,12 +1,12 @@
 package fsm
 
 type Transition struct {
-	Event     EventType
-	NextState State
-	Weight    float64
+	Event     EventType `json:"event" yaml:"event"`
+	NextState State     `json:"next,omitempty" yaml:"next,omitempty"` // back 이벤트는 비워 둠
+	Weight    float64   `json:"weight" yaml:"weight"`
 }
 
-// Transitions defines the user behavior model.
+// Transitions defines the built-in user behavior model (see DefaultModel).
 // - Key: current state
 // - Value: possible transitions from that state
 var Transitions = map[State][]Transition{
@@ -30,8 +30,11 @@ var Transitions = map[State][]Transition{
 		// 홈/첫 페이지 조회 (머무름)
 		{Event: EventPageViewed, NextState: StateBrowsing, Weight: 0.05}, // 상태 변화 없이 머무름
 
+		// 장바구니 열기
+		{Event: EventCartViewed, NextState: StateCart, Weight: 0.03},
+
 		// 이탈
-		{Event: EventExit, NextState: StateExit, Weight: 0.05},
+		{Event: EventExit, NextState: StateExit, Weight: 0.02},
 	},
 
 	// =========================================================
@@ -102,13 +105,40 @@ var Transitions = map[State][]Transition{
 	// Level 3: AddToCart (전환 직전)
 	// =========================================================
 	StateAddToCart: {
-		// 구매 확정
-		{Event: EventPurchased, NextState: StatePurchase, Weight: 0.7},
+		// 장바구니 전체 구매
+		{Event: EventPurchased, NextState: StatePurchase, Weight: 0.4},
+
+		// 장바구니 확인
+		{Event: EventCartViewed, NextState: StateCart, Weight: 0.2},
+
+		// 쇼핑 계속 (다른 상품을 더 담으러 홈으로)
+		{Event: EventPageViewed, NextState: StateBrowsing, Weight: 0.15},
 
 		// 뒤로 → 상세
-		{Event: EventBack, NextState: "", Weight: 0.2},
+		{Event: EventBack, NextState: "", Weight: 0.15},
 
-		// 이탈
+		// 이탈 (장바구니 이탈 분석 대상)
+		{Event: EventExit, NextState: StateExit, Weight: 0.1},
+	},
+
+	// =========================================================
+	// Level 3: Cart (장바구니 화면)
+	// =========================================================
+	StateCart: {
+		// 장바구니 전체 구매
+		{Event: EventPurchased, NextState: StatePurchase, Weight: 0.35},
+
+		// 상품 빼기 / 수량 변경 (장바구니 화면에 머무름)
+		{Event: EventRemoveFromCart, NextState: StateCart, Weight: 0.15},
+		{Event: EventUpdateQuantity, NextState: StateCart, Weight: 0.15},
+
+		// 쇼핑 계속
+		{Event: EventPageViewed, NextState: StateBrowsing, Weight: 0.15},
+
+		// 뒤로
+		{Event: EventBack, NextState: "", Weight: 0.1},
+
+		// 이탈 (장바구니 이탈 분석 대상)
 		{Event: EventExit, NextState: StateExit, Weight: 0.1},
 	},
 
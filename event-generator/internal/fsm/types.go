@@ -13,6 +13,7 @@ const (
 	StateNextPage      State = "nextpage"
 	StateClick         State = "click"
 	StateAddToCart     State = "addtocart"
+	StateCart          State = "cart" // 장바구니 화면
 	StatePurchase      State = "purchase"
 	StateExit          State = "exit" // terminal
 	StateNone          State = ""     // ← 추가 (Back 처리용)
@@ -27,6 +28,9 @@ const (
 	EventCategoryClicked EventType = "category_clicked"
 	EventAddToCart       EventType = "add_to_cart"
 	EventPurchased       EventType = "purchased"
+	EventCartViewed      EventType = "cart_viewed"
+	EventRemoveFromCart  EventType = "remove_from_cart"
+	EventUpdateQuantity  EventType = "update_quantity"
 	EventBack            EventType = "back"
 	EventExit            EventType = "exit"
 )
@@ -1,65 +1,41 @@
 package fsm
 
 import (
-	"math/rand/v2" // v1 대신 v2를 사용합니다.
+	"math/rand/v2"
 )
 
 // 상태 랜덤 선택용 함수
-// [수정] 인자에서 r *rand.Rand 제거. 이제 전역 rand.Float64()를 사용합니다.
-func chooseTransition(ts []Transition) *Transition {
+// r 은 세션 전용 난수 스트림입니다. (seed 모드에서 재현 가능)
+// bias 는 이벤트별 가중치 배수이며 유저 프로필에 따라 전이 확률을 기울입니다.
+func chooseTransition(r *rand.Rand, ts []Transition, bias func(EventType) float64) *Transition {
 	total := 0.0
 	for _, t := range ts {
-		total += t.Weight
+		total += t.Weight * bias(t.Event)
+	}
+	if total <= 0 {
+		// 배수 때문에 모든 전이가 막히면 모델 가중치 그대로 선택
+		bias = noBias
+		for _, t := range ts {
+			total += t.Weight
+		}
 	}
 
-	// v2의 전역 Float64()는 여러 고루틴이 동시에 호출해도 안전합니다.
-	p := rand.Float64() * total
+	p := r.Float64() * total
 	acc := 0.0
 
-	for _, t := range ts {
-		acc += t.Weight
-		if p <= acc {
-			return &t
+	for i := range ts {
+		w := ts[i].Weight * bias(ts[i].Event)
+		if w <= 0 {
+			continue
+		}
+		acc += w
+		if p < acc {
+			return &ts[i]
 		}
 	}
 	return nil
 }
 
-// 검색어 세팅용 함수
-// [수정] 인자에서 r *rand.Rand 제거. 전역 rand.IntN()을 사용합니다.
-func FakeKeyword() string {
-	keywords := []string{
-		// 국가 검색어
-		"홍콩", "대만", "마카오", "싱가포르", "말레이시아", "태국", "UAE", "미국",
-
-		// 🇭🇰 홍콩
-		"홍콩 디즈니", "코타이젯", "홍콩 터보젯", "피크트램", "옹핑 케이블카",
-
-		// 🇹🇼 대만
-		"국립박물관", "딘 타이 펑", "타이페이 101", "Easy 심카드",
-
-		// 🇲🇴 마카오
-		"마카오 오픈 탑 버스", "마카오 해리 포터", "마카오 터보젯", "타워 360", "마카오 전망대",
-
-		// 🇸🇬 싱가포르
-		"가든스 바이 더 베이", "유니버셜 스튜디오 싱가포르", "윙스 오브 타임",
-		"싱가포르 플라이어", "리버크루즈", "나이트 사파리",
-
-		// 🇲🇾 말레이시아
-		"레고랜드", "슈퍼파크 말레이시아", "썬웨이 라군",
-
-		// 🇹🇭 태국
-		"5G 심카드", "진리의 성전", "마하나콘 전망대", "푸켓 아쿠아리움",
-
-		// 🇦🇪 UAE
-		"카스르 알 와탄", "페라리 월드 아부다비", "루브르 아부다비",
-		"부르즈 할리파", "더 뷰 앳 더 팜", "글로벌 빌리지 두바이",
-
-		// 🇺🇸 미국
-		"미국 자연사 박물관", "캘리포니아 디즈니",
-		"LA 빅 버스", "MoMA", "탑 오브 더 락",
-	}
-
-	// rand.Intn(len) -> rand.IntN(len) (v2는 대문자 N입니다)
-	return keywords[rand.IntN(len(keywords))]
+func noBias(EventType) float64 {
+	return 1
 }
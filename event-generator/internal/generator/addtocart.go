@@ -2,11 +2,11 @@ package generator
 
 import (
 	"event-generator/internal/fsm"
-	"math/rand/v2"
 )
 
 // genAddToCart
 func (g *PayloadGenerator) genAddToCart(session fsm.Session, eventType string) map[string]any {
+	r := session.Rand()
 	payload := map[string]any{}
 
 	// 1. 공통 페이로드: 세션에서 상품 정보 및 수량 불러오기
@@ -24,7 +24,7 @@ func (g *PayloadGenerator) genAddToCart(session fsm.Session, eventType string) m
 	payload["quantity"] = lastQuantity // Click 단계에서 생성된 수량
 
 	// 기본 체류 시간 (장바구니 확인 시간)
-	payload["stay_sec"] = rand.IntN(20) + 5
+	payload["stay_sec"] = r.IntN(20) + 5
 
 	// 2. 이벤트별 분기 처리
 	switch eventType {
@@ -37,9 +37,21 @@ func (g *PayloadGenerator) genAddToCart(session fsm.Session, eventType string) m
 		// 장바구니에서 다시 상품 리스트나 상세로 돌아감
 		payload["action"] = "back_to_previous"
 
+	case string(fsm.EventPageViewed):
+		// 다른 상품을 더 담으러 홈으로
+		payload["action"] = "continue_shopping"
+
 	case string(fsm.EventExit):
 		// 장바구니에 담아만 두고 앱을 종료 (Cart Abandonment 분석 대상)
-		payload["exit_reason"] = "user_left_with_items"
+		payload["exit_reason"] = "user_left"
+		if len(session.GetCart()) > 0 {
+			payload["exit_reason"] = "user_left_with_items"
+		}
+	}
+
+	// 장바구니 상태
+	for k, v := range cartSummary(session.GetCart()) {
+		payload[k] = v
 	}
 
 	return payload
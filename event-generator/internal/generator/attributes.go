@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"event-generator/internal/event"
+	"event-generator/internal/fsm"
+	"event-generator/internal/user"
+	"math/rand/v2"
+)
+
+// 세션 유입 경로
+const (
+	ReferrerOrganic    = "organic"
+	ReferrerAdCampaign = "ad_campaign"
+	ReferrerPush       = "push"
+	ReferrerDeepLink   = "deep_link"
+)
+
+// 광고 유입 시 캠페인 이름 (referrer = "ad_campaign:<name>")
+var adCampaigns = []string{"google_search", "instagram_feed", "naver_brand", "youtube_preroll"}
+
+// 상태별 화면 이름 (browsing / eventbrowsing 은 세션의 페이지 정보를 사용)
+var statePages = map[fsm.State]string{
+	fsm.StateSearch:    "search_results",
+	fsm.StateNextPage:  "search_results",
+	fsm.StateClick:     "product_detail",
+	fsm.StateAddToCart: "cart",
+	fsm.StateCart:      "cart",
+	fsm.StatePurchase:  "order_complete",
+}
+
+// Populate : Extra 로만 흘려보내던 정보를 타입이 있는 EventAttributes 필드로 채웁니다.
+// Generate 이후에 호출되므로 이번 Step 에서 선택한 상품/페이지가 반영되어 있습니다.
+func (g *PayloadGenerator) Populate(ev *event.Event, session *user.Session) {
+	attrs := &ev.Attributes
+	state := session.GetState()
+
+	// 1. 유입 경로: 세션의 첫 이벤트에서 한 번 정하고 유지
+	if session.GetReferrer() == "" {
+		session.SetReferrer(pickReferrer(session.Rand()))
+	}
+	attrs.Referrer = session.GetReferrer()
+	attrs.Device = session.GetDevice()
+	attrs.User = session.UserInfo()
+
+	// 2. 현재 화면
+	attrs.Page = g.Page(session)
+
+	// 3. 검색 컨텍스트
+	if ev.EventType == string(fsm.EventSearchSubmitted) || state == fsm.StateSearch || state == fsm.StateNextPage {
+		attrs.Query = session.GetSearchKeyword()
+	}
+
+	// 4. 상품 관련 이벤트
+	prev := fsm.State(attrs.PrevState)
+	if isProductEvent(ev.EventType, prev) {
+		attrs.Product = g.productInfo(session, ev.EventType)
+	}
+
+	// 5. 장바구니 / 주문 상품 목록
+	switch {
+	case ev.EventType == string(fsm.EventPurchased):
+		attrs.Items = g.itemInfos(session.GetLastOrder())
+	case isCartEvent(ev.EventType) || isCartState(prev):
+		attrs.Items = g.itemInfos(session.GetCart())
+	}
+}
+
+// Page : 세션의 현재 상태에서 보고 있는 화면
+func (g *PayloadGenerator) Page(session *user.Session) string {
+	switch state := session.GetState(); state {
+	case fsm.StateBrowsing:
+		if page := session.GetPageType(); page != "" {
+			return page
+		}
+		return "home"
+	case fsm.StateEventBrowsing:
+		return session.GetEventPage()
+	default:
+		return statePages[state]
+	}
+}
+
+// isProductEvent : 상품을 대상으로 한 행동이거나, 상품 화면(상세/장바구니/결제)에서 일어난 이벤트
+// 카테고리 클릭도 카테고리 안의 상품을 골라 세션에 기억하므로 상품 이벤트로 봅니다.
+func isProductEvent(eventType string, prev fsm.State) bool {
+	switch eventType {
+	case string(fsm.EventProductClicked), string(fsm.EventCategoryClicked), string(fsm.EventAddToCart), string(fsm.EventPurchased),
+		string(fsm.EventRemoveFromCart), string(fsm.EventUpdateQuantity):
+		return true
+	}
+	return prev == fsm.StateClick || prev == fsm.StateAddToCart || prev == fsm.StatePurchase
+}
+
+// isCartEvent : 장바구니 내용을 바꾸거나 보여주는 이벤트
+func isCartEvent(eventType string) bool {
+	switch eventType {
+	case string(fsm.EventAddToCart), string(fsm.EventCartViewed),
+		string(fsm.EventRemoveFromCart), string(fsm.EventUpdateQuantity):
+		return true
+	}
+	return false
+}
+
+func (g *PayloadGenerator) productInfo(session fsm.Session, eventType string) *event.ProductInfo {
+	productID, category, country := session.GetLastPicked()
+	if productID == "" {
+		return nil
+	}
+
+	info := &event.ProductInfo{
+		Country:    country,
+		Category:   category,
+		VendorType: session.GetLastVendor(),
+		ProductID:  productID,
+	}
+	if p, ok := g.catalog.ProductByID(productID); ok {
+		info.Price, _ = p.PriceFor(info.VendorType)
+		info.Currency = p.Currency
+	}
+	// 수량이 의미 있는 행동만 기록
+	if isCartEvent(eventType) || eventType == string(fsm.EventPurchased) {
+		info.Quantity = session.GetLastQuantity()
+	}
+	return info
+}
+
+func (g *PayloadGenerator) itemInfos(lines []fsm.CartLine) []event.ProductInfo {
+	if len(lines) == 0 {
+		return nil
+	}
+	items := make([]event.ProductInfo, 0, len(lines))
+	for _, l := range lines {
+		items = append(items, event.ProductInfo{
+			Country:    l.Country,
+			Category:   l.Category,
+			VendorType: l.Vendor,
+			ProductID:  l.ProductID,
+			Price:      l.UnitPrice,
+			Currency:   l.Currency,
+			Quantity:   l.Quantity,
+		})
+	}
+	return items
+}
+
+// pickReferrer : organic 50% / ad 25% / push 15% / deep link 10%
+func pickReferrer(r *rand.Rand) string {
+	switch n := r.IntN(100); {
+	case n < 50:
+		return ReferrerOrganic
+	case n < 75:
+		return ReferrerAdCampaign + ":" + adCampaigns[r.IntN(len(adCampaigns))]
+	case n < 90:
+		return ReferrerPush
+	default:
+		return ReferrerDeepLink
+	}
+}
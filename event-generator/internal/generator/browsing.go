@@ -2,73 +2,67 @@ package generator
 
 import (
 	"event-generator/internal/fsm"
-	"math/rand/v2" // v1 대신 v2를 사용합니다.
 )
 
 // genBrowsing
 func (g *PayloadGenerator) genBrowsing(session fsm.Session, eventType string) map[string]any {
+	r := session.Rand()
 	payload := map[string]any{}
 
 	switch eventType {
 
 	case string(fsm.EventSearchSubmitted):
 		payload["query"] = session.GetSearchKeyword()
-		// [수정] rand.IntN 사용
-		payload["stay_sec"] = rand.IntN(10) + 1
+		payload["stay_sec"] = r.IntN(10) + 1
 
 	case string(fsm.EventPageViewed):
 		session.SetPageType("first_page")
 		payload["page_type"] = "first_page"
-		// [수정] rand.IntN 사용
-		payload["stay_sec"] = rand.IntN(180) + 5
+		payload["stay_sec"] = r.IntN(180) + 5
 
 	case string(fsm.EventPageClicked):
 		pageTypes := []string{"special_event_category", "recommend_category"}
-		// [수정] rand.IntN 사용
-		pageType := pageTypes[rand.IntN(len(pageTypes))]
+		pageType := pageTypes[r.IntN(len(pageTypes))]
 		session.SetPageType(pageType)
 		payload["page_type"] = pageType
 
 		switch pageType {
 		case "special_event_category":
 			eventPages := []string{"flight_promotion", "referral_promotion", "continent_promotion", "season_promotion"}
-			eventPage := eventPages[rand.IntN(len(eventPages))]
+			eventPage := eventPages[r.IntN(len(eventPages))]
 			session.SetEventPage(eventPage)
 			payload["special_event_category"] = eventPage
 		case "recommend_category":
 			session.SetEventPage("recommend_category")
 			payload["recommend_category"] = "recommend_list_to_friends"
 		}
-		payload["stay_sec"] = rand.IntN(180) + 5
+		payload["stay_sec"] = r.IntN(180) + 5
 
 	case string(fsm.EventProductClicked):
-		// [핵심 수정] pickTopProduct는 이제 인자를 받지 않습니다.
-		// g.rnd를 넘기지 않고 바로 호출합니다.
-		product := pickTopProduct()
-		session.SetLastPicked(product.ProductID, product.Category, product.Country)
+		product := g.catalog.RandomHomeProduct(r)
+		rememberProduct(session, r, product)
 
 		payload["product_id"] = product.ProductID
 		payload["product_name"] = product.ProductName
 		payload["category"] = product.Category
 		payload["country"] = product.Country
-		payload["stay_sec"] = rand.IntN(180) + 5
+		payload["stay_sec"] = r.IntN(180) + 5
 
 	case string(fsm.EventCategoryClicked):
 		pageTypes := []string{"country_category", "product_category"}
-		// [수정] rand.IntN 사용
-		pageType := pageTypes[rand.IntN(len(pageTypes))]
+		pageType := pageTypes[r.IntN(len(pageTypes))]
 		session.SetPageType(pageType)
 		payload["page_type"] = pageType
 
 		switch pageType {
 		case "country_category":
-			countries := []string{
-				CountryHongKong, CountryTaiwan, CountryMacau, CountrySingapore,
-				CountryMalaysia, CountryThailand, CountryUAE, CountryUSA,
+			// 선호 여행지가 있으면 절반은 그 나라 카테고리로 이동
+			selectedCountry := g.preferredDestination(session, r, 0.5)
+			if selectedCountry == "" {
+				selectedCountry = g.catalog.RandomCountry(r)
 			}
-			selectedCountry := countries[rand.IntN(len(countries))]
-			if product, ok := GetRandomProductByCountry(selectedCountry); ok {
-				session.SetLastPicked(product.ProductID, product.Category, product.Country)
+			if product, ok := g.catalog.RandomProductByCountry(r, selectedCountry); ok {
+				rememberProduct(session, r, product)
 				payload["selected_country"] = selectedCountry
 				payload["product_id"] = product.ProductID
 				payload["product_name"] = product.ProductName
@@ -77,14 +71,9 @@ func (g *PayloadGenerator) genBrowsing(session fsm.Session, eventType string) ma
 			}
 
 		case "product_category":
-			categories := []string{
-				CategoryAttraction, CategoryTransport, CategoryMuseum,
-				CategoryFood, CategoryTour, CategoryShow,
-				CategoryExhibition, CategoryEtc,
-			}
-			selectedCategory := categories[rand.IntN(len(categories))]
-			if product, ok := GetRandomProductByCategory(selectedCategory); ok {
-				session.SetLastPicked(product.ProductID, product.Category, product.Country)
+			selectedCategory := g.catalog.RandomCategory(r)
+			if product, ok := g.catalog.RandomProductByCategory(r, selectedCategory); ok {
+				rememberProduct(session, r, product)
 				payload["selected_category"] = selectedCategory
 				payload["product_id"] = product.ProductID
 				payload["product_name"] = product.ProductName
@@ -92,7 +81,7 @@ func (g *PayloadGenerator) genBrowsing(session fsm.Session, eventType string) ma
 				payload["recommend_category"] = "category_navigation_list"
 			}
 		}
-		payload["stay_sec"] = rand.IntN(180) + 5
+		payload["stay_sec"] = r.IntN(180) + 5
 
 	case string(fsm.EventExit):
 		payload["exit_reason"] = "user_left"
@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"event-generator/internal/fsm"
+	"slices"
+)
+
+// genCart
+// 장바구니 화면 이벤트 (cart_viewed / remove_from_cart / update_quantity)
+func (g *PayloadGenerator) genCart(session fsm.Session, eventType string) map[string]any {
+	r := session.Rand()
+	payload := map[string]any{}
+	cart := slices.Clone(session.GetCart())
+
+	switch eventType {
+	case string(fsm.EventCartViewed):
+		payload["stay_sec"] = r.IntN(30) + 5
+
+	case string(fsm.EventRemoveFromCart):
+		if len(cart) == 0 {
+			payload["action"] = "empty_cart"
+			break
+		}
+		i := r.IntN(len(cart))
+		removed := cart[i]
+		cart = slices.Delete(cart, i, i+1)
+		g.focusLine(session, removed)
+
+		payload["product_id"] = removed.ProductID
+		payload["vendor"] = removed.Vendor
+		payload["removed_quantity"] = removed.Quantity
+
+	case string(fsm.EventUpdateQuantity):
+		if len(cart) == 0 {
+			payload["action"] = "empty_cart"
+			break
+		}
+		i := r.IntN(len(cart))
+		before := cart[i].Quantity
+		cart[i].Quantity = r.IntN(5) + 1
+		g.focusLine(session, cart[i])
+
+		payload["product_id"] = cart[i].ProductID
+		payload["vendor"] = cart[i].Vendor
+		payload["quantity_before"] = before
+		payload["quantity"] = cart[i].Quantity
+	}
+
+	session.SetCart(cart)
+	for k, v := range cartSummary(cart) {
+		payload[k] = v
+	}
+	return payload
+}
+
+// addCartLine : 같은 상품 + 판매처가 이미 있으면 수량을 더하고, 없으면 새 줄을 추가합니다.
+func (g *PayloadGenerator) addCartLine(session fsm.Session, qty int) (fsm.CartLine, bool) {
+	line, ok := g.currentLine(session, qty)
+	if !ok {
+		return fsm.CartLine{}, false
+	}
+
+	cart := slices.Clone(session.GetCart())
+	i := slices.IndexFunc(cart, func(l fsm.CartLine) bool {
+		return l.ProductID == line.ProductID && l.Vendor == line.Vendor
+	})
+	if i >= 0 {
+		cart[i].Quantity += qty
+	} else {
+		cart = append(cart, line)
+	}
+	session.SetCart(cart)
+	return line, true
+}
+
+// currentLine : 세션에서 마지막으로 선택한 상품을 주문 줄로 만듭니다. (바로 구매 / 장바구니 담기)
+func (g *PayloadGenerator) currentLine(session fsm.Session, qty int) (fsm.CartLine, bool) {
+	productID, category, country := session.GetLastPicked()
+	p, ok := g.catalog.ProductByID(productID)
+	if !ok {
+		return fsm.CartLine{}, false
+	}
+
+	vendor := session.GetLastVendor()
+	price, _ := p.PriceFor(vendor)
+	return fsm.CartLine{
+		ProductID: productID,
+		Category:  category,
+		Country:   country,
+		Vendor:    vendor,
+		Quantity:  max(qty, 1),
+		UnitPrice: price,
+		Currency:  p.Currency,
+	}, true
+}
+
+// focusLine : 빼거나 수량을 바꾼 줄을 현재 상품으로 기록합니다. (이벤트의 Product 속성)
+func (g *PayloadGenerator) focusLine(session fsm.Session, l fsm.CartLine) {
+	session.SetLastPicked(l.ProductID, l.Category, l.Country)
+	session.SetLastVendor(l.Vendor)
+	session.SetLastQuantity(l.Quantity)
+}
+
+// cartSummary : 장바구니 줄 수 / 총 수량 / 통화별 금액
+func cartSummary(lines []fsm.CartLine) map[string]any {
+	qty := 0
+	value := map[string]int{}
+	for _, l := range lines {
+		qty += l.Quantity
+		value[l.Currency] += l.Subtotal()
+	}
+	return map[string]any{
+		"cart_lines":    len(lines),
+		"cart_quantity": qty,
+		"cart_value":    value,
+	}
+}
@@ -0,0 +1,391 @@
+package generator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// =======================
+// Catalog
+// =======================
+
+// Offer : 판매처 한 곳의 판매 조건
+type Offer struct {
+	Vendor string `json:"vendor"`
+	Price  int    `json:"price"` // 국가 통화 기준 판매가
+}
+
+type Product struct {
+	ProductID   string  `json:"id"`
+	ProductName string  `json:"name"`
+	Country     string  `json:"country"`
+	Category    string  `json:"category"`
+	Offers      []Offer `json:"offers"`
+
+	Currency string `json:"-"` // 국가 통화 (카탈로그 로드 시 채움)
+}
+
+// PriceFor : 판매처의 판매가
+func (p *Product) PriceFor(vendor string) (int, bool) {
+	for _, o := range p.Offers {
+		if o.Vendor == vendor {
+			return o.Price, true
+		}
+	}
+	return 0, false
+}
+
+// Country : 국가와 판매 통화 (ISO 4217)
+type Country struct {
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+// Catalog 는 상품/국가/카테고리/판매처/가격/홈 노출/검색어를 한 곳에서 관리하며,
+// 모든 조회 인덱스는 이 데이터로부터 만들어집니다.
+type Catalog struct {
+	Countries    []Country         `json:"countries"`
+	Categories   []string          `json:"categories"`
+	Products     []Product         `json:"products"`
+	HomeExposure []string          `json:"home_exposure"`      // 홈 상단 노출 상품 ID
+	Keywords     []string          `json:"keywords,omitempty"` // 검색어 후보 (비어 있으면 국가명 + 상품명)
+	Synonyms     map[string]string `json:"synonyms,omitempty"` // 검색어 → 상품명 / 국가명 / 카테고리
+
+	// 빠른 검색을 위한 인덱스
+	byName     map[string]*Product
+	byID       map[string]*Product
+	byCountry  map[string][]*Product
+	byCategory map[string][]*Product
+	home       []*Product
+}
+
+// =======================
+// Loading
+// =======================
+
+// LoadCatalog : 확장자에 따라 JSON 또는 CSV 카탈로그 파일을 읽고 검증합니다.
+//
+// CSV 는 상품 한 줄당 한 행이며 헤더는 다음과 같습니다.
+//
+//	product_id,product_name,country,currency,category,offers,home_exposure,keywords
+//
+// offers 는 "VendorA:509;VendorB:488", keywords 는 "디즈니;디즈니랜드" 형식이고,
+// 국가/카테고리 목록과 검색어는 행들로부터 만들어집니다.
+func LoadCatalog(path string) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var c *Catalog
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		c = &Catalog{}
+		dec := json.NewDecoder(f)
+		dec.DisallowUnknownFields()
+		err = dec.Decode(c)
+	case ".csv":
+		c, err = parseCatalogCSV(f)
+	default:
+		return nil, fmt.Errorf("unsupported catalog format: %s (use .json or .csv)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse catalog %s: %w", path, err)
+	}
+
+	if err := c.build(); err != nil {
+		return nil, fmt.Errorf("invalid catalog %s: %w", path, err)
+	}
+	return c, nil
+}
+
+var catalogCSVHeader = []string{"product_id", "product_name", "country", "currency", "category", "offers", "home_exposure", "keywords"}
+
+func parseCatalogCSV(r io.Reader) (*Catalog, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(catalogCSVHeader)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range catalogCSVHeader {
+		if strings.TrimSpace(header[i]) != name {
+			return nil, fmt.Errorf("column %d must be %q (got %q)", i+1, name, header[i])
+		}
+	}
+
+	c := &Catalog{Synonyms: map[string]string{}}
+	seenCountry := map[string]string{} // 국가 → 통화
+	seenCategory := map[string]bool{}
+	var synonyms []string // 행 순서대로 (검색어 순서를 실행마다 같게 유지)
+
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p := Product{ProductID: rec[0], ProductName: rec[1], Country: rec[2], Category: rec[4]}
+		for _, part := range splitList(rec[5]) {
+			vendor, price, ok := strings.Cut(part, ":")
+			n, err := strconv.Atoi(strings.TrimSpace(price))
+			if !ok || err != nil {
+				return nil, fmt.Errorf("line %d: offer %q must be vendor:price", line, part)
+			}
+			p.Offers = append(p.Offers, Offer{Vendor: strings.TrimSpace(vendor), Price: n})
+		}
+		c.Products = append(c.Products, p)
+
+		switch cur, ok := seenCountry[p.Country]; {
+		case !ok:
+			seenCountry[p.Country] = rec[3]
+			c.Countries = append(c.Countries, Country{Name: p.Country, Currency: rec[3]})
+		case cur != rec[3]:
+			return nil, fmt.Errorf("line %d: country %q has currency %s, earlier rows use %s", line, p.Country, rec[3], cur)
+		}
+		if !seenCategory[p.Category] {
+			seenCategory[p.Category] = true
+			c.Categories = append(c.Categories, p.Category)
+		}
+		if home, _ := strconv.ParseBool(strings.TrimSpace(rec[6])); home {
+			c.HomeExposure = append(c.HomeExposure, p.ProductID)
+		}
+		for _, kw := range splitList(rec[7]) {
+			c.Synonyms[kw] = p.ProductName
+			synonyms = append(synonyms, kw)
+		}
+	}
+
+	// 검색어: 국가명 + 상품명 + 동의어
+	for _, country := range c.Countries {
+		c.Keywords = append(c.Keywords, country.Name)
+	}
+	for _, p := range c.Products {
+		c.Keywords = append(c.Keywords, p.ProductName)
+	}
+	c.Keywords = append(c.Keywords, synonyms...)
+	return c, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ";") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// =======================
+// Validation & indexing
+// =======================
+
+// build : 카탈로그를 검증하고 조회 인덱스를 만듭니다. 여러 오류가 있으면 모두 모아서 반환합니다.
+func (c *Catalog) build() error {
+	var errs []error
+
+	currency := map[string]string{}
+	for _, country := range c.Countries {
+		switch {
+		case country.Name == "":
+			errs = append(errs, errors.New("country name must not be empty"))
+		case currency[country.Name] != "":
+			errs = append(errs, fmt.Errorf("duplicate country %q", country.Name))
+		case len(country.Currency) != 3:
+			errs = append(errs, fmt.Errorf("country %q: currency must be an ISO 4217 code (got %q)", country.Name, country.Currency))
+		}
+		currency[country.Name] = country.Currency
+	}
+	categories := map[string]bool{}
+	for _, category := range c.Categories {
+		if category == "" || categories[category] {
+			errs = append(errs, fmt.Errorf("invalid or duplicate category %q", category))
+		}
+		categories[category] = true
+	}
+	if len(c.Products) == 0 {
+		errs = append(errs, errors.New("catalog has no products"))
+	}
+
+	c.byName = make(map[string]*Product, len(c.Products))
+	c.byID = make(map[string]*Product, len(c.Products))
+	c.byCountry = make(map[string][]*Product)
+	c.byCategory = make(map[string][]*Product)
+
+	for i := range c.Products {
+		p := &c.Products[i]
+		switch {
+		case p.ProductID == "" || p.ProductName == "":
+			errs = append(errs, fmt.Errorf("product #%d: id and name are required", i+1))
+			continue
+		case c.byID[p.ProductID] != nil:
+			errs = append(errs, fmt.Errorf("product %s: duplicate id", p.ProductID))
+		case c.byName[p.ProductName] != nil:
+			errs = append(errs, fmt.Errorf("product %s: duplicate name %q", p.ProductID, p.ProductName))
+		}
+		if _, ok := currency[p.Country]; !ok {
+			errs = append(errs, fmt.Errorf("product %s: unknown country %q", p.ProductID, p.Country))
+		}
+		if !categories[p.Category] {
+			errs = append(errs, fmt.Errorf("product %s: unknown category %q", p.ProductID, p.Category))
+		}
+		if len(p.Offers) == 0 {
+			errs = append(errs, fmt.Errorf("product %s: at least one vendor offer is required", p.ProductID))
+		}
+		vendors := map[string]bool{}
+		for _, o := range p.Offers {
+			if o.Vendor == "" || vendors[o.Vendor] {
+				errs = append(errs, fmt.Errorf("product %s: invalid or duplicate vendor %q", p.ProductID, o.Vendor))
+			}
+			if o.Price <= 0 {
+				errs = append(errs, fmt.Errorf("product %s: vendor %s price must be > 0 (got %d)", p.ProductID, o.Vendor, o.Price))
+			}
+			vendors[o.Vendor] = true
+		}
+
+		p.Currency = currency[p.Country]
+		c.byName[p.ProductName] = p
+		c.byID[p.ProductID] = p
+		c.byCountry[p.Country] = append(c.byCountry[p.Country], p)
+		c.byCategory[p.Category] = append(c.byCategory[p.Category], p)
+	}
+
+	c.home = c.home[:0]
+	for _, id := range c.HomeExposure {
+		p, ok := c.byID[id]
+		if !ok {
+			errs = append(errs, fmt.Errorf("home_exposure: unknown product id %q", id))
+			continue
+		}
+		c.home = append(c.home, p)
+	}
+	if len(c.home) == 0 {
+		errs = append(errs, errors.New("home_exposure must list at least one product"))
+	}
+
+	for kw, target := range c.Synonyms {
+		if c.byName[target] == nil && c.byCountry[target] == nil && c.byCategory[target] == nil {
+			errs = append(errs, fmt.Errorf("synonym %q: target %q is not a product, country or category", kw, target))
+		}
+	}
+
+	if len(c.Keywords) == 0 {
+		for _, country := range c.Countries {
+			c.Keywords = append(c.Keywords, country.Name)
+		}
+		for _, p := range c.Products {
+			c.Keywords = append(c.Keywords, p.ProductName)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// =======================
+// Lookups
+// =======================
+
+// ProductByName : 상품명으로 정확히 일치하는 상품 정보 반환
+func (c *Catalog) ProductByName(name string) (*Product, bool) {
+	p, ok := c.byName[name]
+	return p, ok
+}
+
+// ProductByID : 상품 ID 로 상품 정보 반환 (세션에 저장된 LastProductID 조회용)
+func (c *Catalog) ProductByID(id string) (*Product, bool) {
+	p, ok := c.byID[id]
+	return p, ok
+}
+
+// RandomProductByCountry : 해당 국가 상품 중 랜덤 1개 반환
+func (c *Catalog) RandomProductByCountry(r *rand.Rand, country string) (*Product, bool) {
+	return pickOne(r, c.byCountry[country])
+}
+
+// RandomProductByCategory : 해당 카테고리 상품 중 랜덤 1개 반환
+func (c *Catalog) RandomProductByCategory(r *rand.Rand, category string) (*Product, bool) {
+	return pickOne(r, c.byCategory[category])
+}
+
+// RandomHomeProduct : 홈 노출 리스트 중 하나를 랜덤하게 반환
+func (c *Catalog) RandomHomeProduct(r *rand.Rand) *Product {
+	return c.home[r.IntN(len(c.home))]
+}
+
+// RandomCountry / RandomCategory : 카테고리 페이지 탐색용
+func (c *Catalog) RandomCountry(r *rand.Rand) string {
+	return c.Countries[r.IntN(len(c.Countries))].Name
+}
+
+// CountryNames : 카탈로그 국가 이름 목록 (유저 선호 여행지 후보)
+func (c *Catalog) CountryNames() []string {
+	names := make([]string, len(c.Countries))
+	for i, country := range c.Countries {
+		names[i] = country.Name
+	}
+	return names
+}
+
+func (c *Catalog) RandomCategory(r *rand.Rand) string {
+	return c.Categories[r.IntN(len(c.Categories))]
+}
+
+// RandomKeyword : 검색어 후보 중 하나
+func (c *Catalog) RandomKeyword(r *rand.Rand) string {
+	return c.Keywords[r.IntN(len(c.Keywords))]
+}
+
+// Distinguish : 검색어가 상품명/국가명/카테고리 중 무엇인지 판별하고 해당 상품을 반환합니다.
+// 동의어는 대상 검색어로 바꾼 뒤 판별합니다.
+func (c *Catalog) Distinguish(r *rand.Rand, query string) (*Product, string) {
+	if target, ok := c.Synonyms[query]; ok {
+		query = target
+	}
+
+	// 1. 상품명 일치 확인
+	if p, ok := c.byName[query]; ok {
+		return p, "product_match"
+	}
+
+	// 2. 국가명 일치 확인
+	if p, ok := c.RandomProductByCountry(r, query); ok {
+		return p, "country_match"
+	}
+
+	// 3. 카테고리명 일치 확인
+	if p, ok := c.RandomProductByCategory(r, query); ok {
+		return p, "category_match"
+	}
+
+	// 4. 부분 일치 체크
+	// map 순회 순서는 실행마다 달라지므로 원본 슬라이스 순서로 확인합니다. (seed 모드 재현성)
+	for i := range c.Products {
+		if strings.Contains(c.Products[i].ProductName, query) {
+			return &c.Products[i], "partial_match"
+		}
+	}
+
+	return nil, "no_match"
+}
+
+func pickOne(r *rand.Rand, list []*Product) (*Product, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+	return list[r.IntN(len(list))], true
+}
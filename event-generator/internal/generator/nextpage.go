@@ -3,12 +3,12 @@ package generator
 import (
 	"event-generator/internal/fsm"
 	"log"
-	"math/rand/v2" // v1 대신 v2를 사용합니다.
 )
 
 // GenerateNextPagePayload
 // NextPage 상태 진입 시 payload 생성
 func (g *PayloadGenerator) genNextPage(session fsm.Session, eventType string) map[string]any {
+	r := session.Rand()
 	payload := map[string]any{}
 
 	// 기본 검색 컨텍스트 유지
@@ -19,8 +19,7 @@ func (g *PayloadGenerator) genNextPage(session fsm.Session, eventType string) ma
 	payload["page_index"] = session.GetPageIndex()
 
 	// 체류 시간
-	// [수정] g.rnd.Intn -> rand.IntN (v2 전역 함수 사용)
-	payload["stay_sec"] = rand.IntN(40) + 10
+	payload["stay_sec"] = r.IntN(40) + 10
 
 	switch eventType {
 	case string(fsm.EventPageViewed):
@@ -37,7 +36,7 @@ func (g *PayloadGenerator) genNextPage(session fsm.Session, eventType string) ma
 		keyword := session.GetSearchKeyword()
 
 		// 1. 상품/국가/카테고리 판별 함수 사용
-		product, searchType := DistinguishAndGetProduct(keyword)
+		product, searchType := g.catalog.Distinguish(r, keyword)
 
 		// 2. 방어 로직: 검색 결과가 아예 없는 경우
 		if product == nil {
@@ -46,7 +45,7 @@ func (g *PayloadGenerator) genNextPage(session fsm.Session, eventType string) ma
 		}
 
 		// 3. 세션 업데이트
-		session.SetLastPicked(product.ProductID, product.Category, product.Country)
+		rememberProduct(session, r, product)
 
 		// 4. 페이로드 구성
 		payload["product_id"] = product.ProductID
@@ -58,16 +57,14 @@ func (g *PayloadGenerator) genNextPage(session fsm.Session, eventType string) ma
 	case string(fsm.EventBack):
 		// 이전 페이지로 돌아감
 		payload["action"] = "back_button_click"
-		// [수정] g.rnd.Intn -> rand.IntN
-		payload["stay_sec"] = rand.IntN(60) + 2
+		payload["stay_sec"] = r.IntN(60) + 2
 
 	case string(fsm.EventExit):
 		// 앱 종료 혹은 이탈
 		payload["exit_reason"] = "user_left"
 
 		// 이탈 전 최종 체류 시간
-		// [수정] g.rnd.Intn -> rand.IntN
-		payload["total_event_stay_sec"] = rand.IntN(50) + 10
+		payload["total_event_stay_sec"] = r.IntN(50) + 10
 	}
 
 	return payload
@@ -5,12 +5,17 @@ import (
 	"event-generator/internal/user"
 )
 
+// 난수는 세션별 스트림(Session.Rand)을 사용합니다.
 type PayloadGenerator struct {
-	// rand/v2 전역 함수를 사용하므로 필드가 필요 없습니다.
+	catalog *Catalog
 }
 
-func NewPayloadGenerator() *PayloadGenerator {
-	return &PayloadGenerator{}
+// NewPayloadGenerator : catalog 가 nil 이면 기본 카탈로그(DefaultCatalog)를 사용합니다.
+func NewPayloadGenerator(catalog *Catalog) *PayloadGenerator {
+	if catalog == nil {
+		catalog = DefaultCatalog()
+	}
+	return &PayloadGenerator{catalog: catalog}
 }
 
 // Generate : 이벤트 타입과 세션 상태에 따라 payload 생성
@@ -27,16 +32,20 @@ func (g *PayloadGenerator) Generate(eventType string, session *user.Session) map
 
 	// 2. 페이지 조회
 	case string(fsm.EventPageViewed):
-		if currState == fsm.StateNextPage {
+		if isCartState(prevState) {
+			eventPayload = g.genAddToCart(session, eventType)
+		} else if currState == fsm.StateNextPage {
 			eventPayload = g.genNextPage(session, eventType)
 		} else {
 			eventPayload = g.genBrowsing(session, eventType)
 		}
 
-	// 3. 상품 클릭
+	// 3. 상품 클릭 (검색 결과 / 홈 노출 상품)
 	case string(fsm.EventProductClicked):
 		if prevState == fsm.StateSearch || prevState == fsm.StateNextPage {
 			eventPayload = g.genSearch(session, eventType)
+		} else if prevState == fsm.StateBrowsing {
+			eventPayload = g.genBrowsing(session, eventType)
 		} else {
 			eventPayload = g.genClick(session, eventType)
 		}
@@ -49,10 +58,16 @@ func (g *PayloadGenerator) Generate(eventType string, session *user.Session) map
 	case string(fsm.EventAddToCart):
 		eventPayload = g.genClick(session, eventType)
 
+	// 장바구니 화면
+	case string(fsm.EventCartViewed), string(fsm.EventRemoveFromCart), string(fsm.EventUpdateQuantity):
+		eventPayload = g.genCart(session, eventType)
+
 	case string(fsm.EventBack):
 		// [수정] 현재 상태가 EventBrowsing이면 전용 로직 호출
 		if currState == fsm.StateEventBrowsing {
 			eventPayload = g.genEventBrowsing(session, eventType)
+		} else if isCartState(prevState) {
+			eventPayload = g.genAddToCart(session, eventType)
 		} else {
 			eventPayload = g.genClick(session, eventType)
 		}
@@ -67,6 +82,8 @@ func (g *PayloadGenerator) Generate(eventType string, session *user.Session) map
 			eventPayload = g.genEventBrowsing(session, eventType)
 		} else if currState == fsm.StatePurchase {
 			eventPayload = g.genPurchase(session, eventType)
+		} else if isCartState(prevState) {
+			eventPayload = g.genAddToCart(session, eventType)
 		} else {
 			eventPayload = g.genBrowsing(session, eventType)
 		}
@@ -87,3 +104,8 @@ func (g *PayloadGenerator) Generate(eventType string, session *user.Session) map
 
 	return eventPayload
 }
+
+// isCartState : 장바구니에 담은 직후이거나 장바구니 화면
+func isCartState(s fsm.State) bool {
+	return s == fsm.StateAddToCart || s == fsm.StateCart
+}
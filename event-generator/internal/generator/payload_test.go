@@ -0,0 +1,83 @@
+package generator_test
+
+import (
+	"testing"
+	"time"
+
+	"event-generator/internal/clock"
+	"event-generator/internal/event"
+	"event-generator/internal/fsm"
+	"event-generator/internal/generator"
+	"event-generator/internal/metrics"
+	"event-generator/internal/rng"
+	"event-generator/internal/user"
+)
+
+// collector : 내보낸 이벤트를 순서대로 모으는 큐
+type collector struct {
+	events []*event.Event
+}
+
+func (c *collector) Push(ev *event.Event) {
+	c.events = append(c.events, ev)
+}
+
+// generate : 작은 유저 풀로 SessionManager 를 steps 번 진행시켜 이벤트를 모읍니다.
+func generate(t *testing.T, seed uint64, steps int) []*event.Event {
+	t.Helper()
+	clk := clock.NewVirtual(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	catalog := generator.DefaultCatalog()
+	up := user.NewUserPool(nil, user.DefaultProfileDistribution(), catalog.CountryNames(), user.DefaultPersonas(), clk)
+	up.EnsureUsers(500)
+
+	var out collector
+	sm := user.NewSessionManager(up, fsm.NewSimpleFSM(nil), generator.NewPayloadGenerator(catalog), &out, metrics.NewInMemory(), 30*time.Minute, 1, clk, rng.NewFactory(seed))
+	for range steps {
+		clk.Advance(500 * time.Millisecond)
+		sm.Step()
+	}
+	return out.events
+}
+
+// TestPurchaseHasOrder : 모든 purchased 이벤트가 주문 정보 (order_id / 통화 / 합계) 를 갖고,
+// 장바구니 담기와 홈 노출 상품 클릭이 상품을 선택하는지 확인합니다.
+func TestPurchaseHasOrder(t *testing.T) {
+	var purchases, carts, homeClicks int
+	for _, ev := range generate(t, 42, 20000) {
+		extra := ev.Attributes.Extra
+		switch {
+		case ev.EventType == string(fsm.EventPurchased):
+			purchases++
+			if extra["order_id"] == nil || extra["currency"] == nil {
+				t.Errorf("%s: purchased without order_id/currency: %v", ev.EventID, extra)
+			} else if extra["total_amount"] == nil && extra["total_by_currency"] == nil {
+				t.Errorf("%s: purchased without a total: %v", ev.EventID, extra)
+			}
+			if len(ev.Attributes.Items) == 0 {
+				t.Errorf("%s: purchased without order items", ev.EventID)
+			}
+			// 주문 단위 값과 섞이지 않도록 판매처 / 단가는 한 줄 주문에만 있어야 함
+			if n, _ := extra["line_count"].(int); n > 1 && (extra["vendor"] != nil || extra["unit_price"] != nil) {
+				t.Errorf("%s: %d-line order has order-level vendor/unit_price: %v", ev.EventID, n, extra)
+			}
+			if extra["currency"] == "MIXED" && extra["total_amount"] != nil {
+				t.Errorf("%s: mixed-currency order has a single total_amount: %v", ev.EventID, extra)
+			}
+
+		case ev.EventType == string(fsm.EventAddToCart):
+			carts++
+			if extra["unit_price"] == nil {
+				t.Errorf("%s: add_to_cart added no cart line: %v", ev.EventID, extra)
+			}
+
+		case ev.EventType == string(fsm.EventProductClicked) && ev.Attributes.PrevState == string(fsm.StateBrowsing):
+			homeClicks++
+			if id, _ := extra["product_id"].(string); id == "" || ev.Attributes.Product == nil {
+				t.Errorf("%s: home product_clicked without a product: %v", ev.EventID, extra)
+			}
+		}
+	}
+	if purchases == 0 || carts == 0 || homeClicks == 0 {
+		t.Fatalf("too few events to check: purchased=%d add_to_cart=%d home product_clicked=%d", purchases, carts, homeClicks)
+	}
+}
@@ -1,18 +1,5 @@
 package generator
 
-import (
-	"math/rand/v2" // v1 대신 v2를 사용합니다.
-	"strings"
-)
-
-type Product struct {
-	ProductID   string
-	ProductName string
-	Country     string
-	Category    string
-	Vendors     []string
-}
-
 // 카테고리 상수
 const (
 	CategoryAttraction = "attraction"
@@ -37,156 +24,128 @@ const (
 	CountryUSA       = "미국"
 )
 
-// 빠른 검색을 위한 전역 변수
-var (
-	productMap  map[string]*Product
-	countryMap  map[string][]*Product
-	categoryMap map[string][]*Product
-)
-
-// 홈 상단 노출 대상 상품명 리스트
-var homeExposureProductNames = []string{
-	"홍콩 디즈니",
-	"유니버셜 스튜디오 싱가포르",
-	"슈퍼파크 말레이시아",
-	"페라리 월드 아부다비",
-	"캘리포니아 디즈니",
-}
-
-// 데이터 초기화
-func init() {
-	productMap = make(map[string]*Product)
-	countryMap = make(map[string][]*Product)
-	categoryMap = make(map[string][]*Product)
-
-	for i := range products {
-		p := &products[i]
-
-		// 1. 이름으로 바로 찾기 (1:1)
-		productMap[p.ProductName] = p
-
-		// 2. 국가별 상품 묶음 (1:N)
-		countryMap[p.Country] = append(countryMap[p.Country], p)
-
-		// 3. 카테고리별 상품 묶음 (1:N)
-		categoryMap[p.Category] = append(categoryMap[p.Category], p)
+// =======================
+// 기본 카탈로그 (catalog 파일을 지정하지 않았을 때 사용)
+// =======================
+// configs/catalog.default.json 과 같은 내용입니다.
+
+// DefaultCatalog : 코드에 정의된 기본 상품 카탈로그
+func DefaultCatalog() *Catalog {
+	c := &Catalog{
+		Countries: []Country{
+			{CountryHongKong, "HKD"},
+			{CountryTaiwan, "TWD"},
+			{CountryMacau, "MOP"},
+			{CountrySingapore, "SGD"},
+			{CountryMalaysia, "MYR"},
+			{CountryThailand, "THB"},
+			{CountryUAE, "AED"},
+			{CountryUSA, "USD"},
+		},
+		Categories: []string{
+			CategoryAttraction, CategoryTransport, CategoryMuseum,
+			CategoryFood, CategoryTour, CategoryShow,
+			CategoryExhibition, CategoryEtc,
+		},
+		Products: append([]Product(nil), defaultProducts...),
+		// 홈 상단 노출 대상 상품
+		HomeExposure: []string{
+			"P001", // 홍콩 디즈니
+			"P016", // 유니버셜 스튜디오 싱가포르
+			"P022", // 슈퍼파크 말레이시아
+			"P029", // 페라리 월드 아부다비
+			"P035", // 캘리포니아 디즈니
+		},
+		Keywords: append([]string(nil), defaultKeywords...),
+		Synonyms: map[string]string{
+			"USS":   "유니버셜 스튜디오 싱가포르",
+			"두바이":   CountryUAE,
+			"뉴욕":    CountryUSA,
+			"유심":    CategoryEtc,
+			"박물관":   CategoryMuseum,
+			"디즈니랜드": "홍콩 디즈니",
+		},
 	}
-}
-
-// GetProductByName: 상품명으로 정확히 일치하는 상품 정보 반환
-func GetProductByName(name string) (*Product, bool) {
-	p, ok := productMap[name]
-	return p, ok
-}
-
-// GetRandomProductByCountry: 국가명으로 검색하여 해당 국가 상품 중 랜덤 1개 반환
-func GetRandomProductByCountry(country string) (*Product, bool) {
-	productList, ok := countryMap[country]
-	if !ok || len(productList) == 0 {
-		return nil, false
+	if err := c.build(); err != nil {
+		panic("default catalog: " + err.Error())
 	}
-
-	// [수정] rand.Intn -> rand.IntN (v2 전역 함수 사용)
-	randomIndex := rand.IntN(len(productList))
-	return productList[randomIndex], true
+	return c
 }
 
-// GetRandomProductByCategory: 카테고리명으로 검색하여 해당 카테고리 상품 중 랜덤 1개 반환
-func GetRandomProductByCategory(category string) (*Product, bool) {
-	productList, ok := categoryMap[category]
-	if !ok || len(productList) == 0 {
-		return nil, false
-	}
+// 검색어 목록 (국가명 / 상품명 / 부분 상품명 / 동의어)
+var defaultKeywords = []string{
+	// 국가 검색어
+	"홍콩", "대만", "마카오", "싱가포르", "말레이시아", "태국", "UAE", "미국",
 
-	// [수정] rand.Intn -> rand.IntN (v2 전역 함수 사용)
-	randomIndex := rand.IntN(len(productList))
-	return productList[randomIndex], true
-}
+	// 🇭🇰 홍콩
+	"홍콩 디즈니", "코타이젯", "홍콩 터보젯", "피크트램", "옹핑 케이블카",
 
-// pickTopProduct: 홈 노출 리스트 중 하나를 랜덤하게 뽑아 상세 정보 반환
-// [수정] 인자에서 r *rand.Rand 제거
-func pickTopProduct() *Product {
-	// [수정] rand.Intn -> rand.IntN (v2 전역 함수 사용)
-	name := homeExposureProductNames[rand.IntN(len(homeExposureProductNames))]
+	// 🇹🇼 대만
+	"국립박물관", "딘 타이 펑", "타이페이 101", "Easy 심카드",
 
-	p, ok := GetProductByName(name)
-	if !ok {
-		panic("exposure product name not found in map: " + name)
-	}
+	// 🇲🇴 마카오
+	"마카오 오픈 탑 버스", "마카오 해리 포터", "마카오 터보젯", "타워 360", "마카오 전망대",
 
-	return p
-}
+	// 🇸🇬 싱가포르
+	"가든스 바이 더 베이", "유니버셜 스튜디오 싱가포르", "윙스 오브 타임",
+	"싱가포르 플라이어", "리버크루즈", "나이트 사파리",
 
-func DistinguishAndGetProduct(query string) (*Product, string) {
-	// 1. 상품명 일치 확인
-	if p, ok := GetProductByName(query); ok {
-		return p, "product_match"
-	}
+	// 🇲🇾 말레이시아
+	"레고랜드", "슈퍼파크 말레이시아", "썬웨이 라군",
 
-	// 2. 국가명 일치 확인
-	if _, ok := countryMap[query]; ok {
-		p, found := GetRandomProductByCountry(query)
-		if found {
-			return p, "country_match"
-		}
-	}
+	// 🇹🇭 태국
+	"5G 심카드", "진리의 성전", "마하나콘 전망대", "푸켓 아쿠아리움",
 
-	// 3. 카테고리명 일치 확인
-	if _, ok := categoryMap[query]; ok {
-		p, found := GetRandomProductByCategory(query)
-		if found {
-			return p, "category_match"
-		}
-	}
+	// 🇦🇪 UAE
+	"카스르 알 와탄", "페라리 월드 아부다비", "루브르 아부다비",
+	"부르즈 할리파", "더 뷰 앳 더 팜", "글로벌 빌리지 두바이",
 
-	// 4. 부분 일치 체크
-	for name, p := range productMap {
-		if strings.Contains(name, query) {
-			return p, "partial_match"
-		}
-	}
+	// 🇺🇸 미국
+	"미국 자연사 박물관", "캘리포니아 디즈니",
+	"LA 빅 버스", "MoMA", "탑 오브 더 락",
 
-	return nil, "no_match"
+	// 동의어
+	"USS", "두바이", "뉴욕", "유심", "박물관", "디즈니랜드",
 }
 
-// 상품 원본 데이터
-var products = []Product{
-	{ProductID: "P001", ProductName: "홍콩 디즈니", Country: CountryHongKong, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB", "VendorC"}},
-	{ProductID: "P002", ProductName: "코타이젯", Country: CountryHongKong, Category: CategoryTransport, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P003", ProductName: "홍콩 터보젯", Country: CountryHongKong, Category: CategoryTransport, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P004", ProductName: "피크트램", Country: CountryHongKong, Category: CategoryTransport, Vendors: []string{"VendorA", "VendorB"}},
-	{ProductID: "P005", ProductName: "옹핑 케이블카", Country: CountryHongKong, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB", "VendorC"}},
-	{ProductID: "P006", ProductName: "대만 국립박물관", Country: CountryTaiwan, Category: CategoryMuseum, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P007", ProductName: "딘 타이 펑", Country: CountryTaiwan, Category: CategoryFood, Vendors: []string{"VendorB", "VendorC"}},
-	{ProductID: "P008", ProductName: "타이페이 101", Country: CountryTaiwan, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB"}},
-	{ProductID: "P009", ProductName: "Easy 심카드", Country: CountryTaiwan, Category: CategoryEtc, Vendors: []string{"VendorC"}},
-	{ProductID: "P010", ProductName: "마카오 오픈 탑 버스", Country: CountryMacau, Category: CategoryTransport, Vendors: []string{"VendorA", "VendorB"}},
-	{ProductID: "P011", ProductName: "마카오 해리 포터", Country: CountryMacau, Category: CategoryExhibition, Vendors: []string{"VendorA"}},
-	{ProductID: "P012", ProductName: "마카오 터보젯", Country: CountryMacau, Category: CategoryTransport, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P013", ProductName: "타워 360", Country: CountryMacau, Category: CategoryAttraction, Vendors: []string{"VendorB", "VendorC"}},
-	{ProductID: "P014", ProductName: "마카오 전망대", Country: CountryMacau, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB"}},
-	{ProductID: "P015", ProductName: "가든스 바이 더 베이", Country: CountrySingapore, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB", "VendorC"}},
-	{ProductID: "P016", ProductName: "유니버셜 스튜디오 싱가포르", Country: CountrySingapore, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P017", ProductName: "윙스 오브 타임", Country: CountrySingapore, Category: CategoryShow, Vendors: []string{"VendorA"}},
-	{ProductID: "P018", ProductName: "싱가포르 플라이어", Country: CountrySingapore, Category: CategoryAttraction, Vendors: []string{"VendorB"}},
-	{ProductID: "P019", ProductName: "리버크루즈", Country: CountrySingapore, Category: CategoryTour, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P020", ProductName: "나이트 사파리", Country: CountrySingapore, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB"}},
-	{ProductID: "P021", ProductName: "레고랜드", Country: CountryMalaysia, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P022", ProductName: "슈퍼파크 말레이시아", Country: CountryMalaysia, Category: CategoryAttraction, Vendors: []string{"VendorB"}},
-	{ProductID: "P023", ProductName: "5G 심카드", Country: CountryMalaysia, Category: CategoryEtc, Vendors: []string{"VendorC"}},
-	{ProductID: "P024", ProductName: "썬웨이 라군", Country: CountryMalaysia, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB"}},
-	{ProductID: "P025", ProductName: "진리의 성전", Country: CountryThailand, Category: CategoryAttraction, Vendors: []string{"VendorA"}},
-	{ProductID: "P026", ProductName: "마하나콘 전망대", Country: CountryThailand, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB"}},
-	{ProductID: "P027", ProductName: "푸켓 아쿠아리움", Country: CountryThailand, Category: CategoryAttraction, Vendors: []string{"VendorC"}},
-	{ProductID: "P028", ProductName: "카스르 알 와탄", Country: CountryUAE, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB"}},
-	{ProductID: "P029", ProductName: "페라리 월드 아부다비", Country: CountryUAE, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P030", ProductName: "루브르 아부다비", Country: CountryUAE, Category: CategoryMuseum, Vendors: []string{"VendorB", "VendorC"}},
-	{ProductID: "P031", ProductName: "부르즈 할리파", Country: CountryUAE, Category: CategoryMuseum, Vendors: []string{"VendorA", "VendorB", "VendorC"}},
-	{ProductID: "P032", ProductName: "더 뷰 앳 더 팜", Country: CountryUAE, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P033", ProductName: "글로벌 빌리지 두바이", Country: CountryUAE, Category: CategoryAttraction, Vendors: []string{"VendorB"}},
-	{ProductID: "P034", ProductName: "미국 자연사 박물관", Country: CountryUSA, Category: CategoryMuseum, Vendors: []string{"VendorA"}},
-	{ProductID: "P035", ProductName: "캘리포니아 디즈니", Country: CountryUSA, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorC"}},
-	{ProductID: "P036", ProductName: "LA 빅 버스 투어", Country: CountryUSA, Category: CategoryTour, Vendors: []string{"VendorB"}},
-	{ProductID: "P037", ProductName: "MoMA 현대 미술관", Country: CountryUSA, Category: CategoryMuseum, Vendors: []string{"VendorC"}},
-	{ProductID: "P038", ProductName: "탑 오브 더 락", Country: CountryUSA, Category: CategoryAttraction, Vendors: []string{"VendorA", "VendorB"}},
+// 상품 원본 데이터 (판매가는 국가 통화 기준)
+var defaultProducts = []Product{
+	{ProductID: "P001", ProductName: "홍콩 디즈니", Country: CountryHongKong, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 509}, {"VendorB", 488}, {"VendorC", 529}}},
+	{ProductID: "P002", ProductName: "코타이젯", Country: CountryHongKong, Category: CategoryTransport, Offers: []Offer{{"VendorA", 257}, {"VendorC", 267}}},
+	{ProductID: "P003", ProductName: "홍콩 터보젯", Country: CountryHongKong, Category: CategoryTransport, Offers: []Offer{{"VendorA", 240}, {"VendorC", 250}}},
+	{ProductID: "P004", ProductName: "피크트램", Country: CountryHongKong, Category: CategoryTransport, Offers: []Offer{{"VendorA", 51}, {"VendorB", 49}}},
+	{ProductID: "P005", ProductName: "옹핑 케이블카", Country: CountryHongKong, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 183}, {"VendorB", 176}, {"VendorC", 190}}},
+	{ProductID: "P006", ProductName: "대만 국립박물관", Country: CountryTaiwan, Category: CategoryMuseum, Offers: []Offer{{"VendorA", 279}, {"VendorC", 290}}},
+	{ProductID: "P007", ProductName: "딘 타이 펑", Country: CountryTaiwan, Category: CategoryFood, Offers: []Offer{{"VendorB", 558}, {"VendorC", 605}}},
+	{ProductID: "P008", ProductName: "타이페이 101", Country: CountryTaiwan, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 558}, {"VendorB", 536}}},
+	{ProductID: "P009", ProductName: "Easy 심카드", Country: CountryTaiwan, Category: CategoryEtc, Offers: []Offer{{"VendorC", 363}}},
+	{ProductID: "P010", ProductName: "마카오 오픈 탑 버스", Country: CountryMacau, Category: CategoryTransport, Offers: []Offer{{"VendorA", 165}, {"VendorB", 158}}},
+	{ProductID: "P011", ProductName: "마카오 해리 포터", Country: CountryMacau, Category: CategoryExhibition, Offers: []Offer{{"VendorA", 206}}},
+	{ProductID: "P012", ProductName: "마카오 터보젯", Country: CountryMacau, Category: CategoryTransport, Offers: []Offer{{"VendorA", 241}, {"VendorC", 251}}},
+	{ProductID: "P013", ProductName: "타워 360", Country: CountryMacau, Category: CategoryAttraction, Offers: []Offer{{"VendorB", 169}, {"VendorC", 184}}},
+	{ProductID: "P014", ProductName: "마카오 전망대", Country: CountryMacau, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 106}, {"VendorB", 102}}},
+	{ProductID: "P015", ProductName: "가든스 바이 더 베이", Country: CountrySingapore, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 27}, {"VendorB", 26}, {"VendorC", 29}}},
+	{ProductID: "P016", ProductName: "유니버셜 스튜디오 싱가포르", Country: CountrySingapore, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 103}, {"VendorC", 107}}},
+	{ProductID: "P017", ProductName: "윙스 오브 타임", Country: CountrySingapore, Category: CategoryShow, Offers: []Offer{{"VendorA", 22}}},
+	{ProductID: "P018", ProductName: "싱가포르 플라이어", Country: CountrySingapore, Category: CategoryAttraction, Offers: []Offer{{"VendorB", 39}}},
+	{ProductID: "P019", ProductName: "리버크루즈", Country: CountrySingapore, Category: CategoryTour, Offers: []Offer{{"VendorA", 25}, {"VendorC", 27}}},
+	{ProductID: "P020", ProductName: "나이트 사파리", Country: CountrySingapore, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 61}, {"VendorB", 58}}},
+	{ProductID: "P021", ProductName: "레고랜드", Country: CountryMalaysia, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 240}, {"VendorC", 250}}},
+	{ProductID: "P022", ProductName: "슈퍼파크 말레이시아", Country: CountryMalaysia, Category: CategoryAttraction, Offers: []Offer{{"VendorB", 122}}},
+	{ProductID: "P023", ProductName: "5G 심카드", Country: CountryMalaysia, Category: CategoryEtc, Offers: []Offer{{"VendorC", 42}}},
+	{ProductID: "P024", ProductName: "썬웨이 라군", Country: CountryMalaysia, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 183}, {"VendorB", 176}}},
+	{ProductID: "P025", ProductName: "진리의 성전", Country: CountryThailand, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 658}}},
+	{ProductID: "P026", ProductName: "마하나콘 전망대", Country: CountryThailand, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 1000}, {"VendorB", 960}}},
+	{ProductID: "P027", ProductName: "푸켓 아쿠아리움", Country: CountryThailand, Category: CategoryAttraction, Offers: []Offer{{"VendorC", 794}}},
+	{ProductID: "P028", ProductName: "카스르 알 와탄", Country: CountryUAE, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 67}, {"VendorB", 64}}},
+	{ProductID: "P029", ProductName: "페라리 월드 아부다비", Country: CountryUAE, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 256}, {"VendorC", 266}}},
+	{ProductID: "P030", ProductName: "루브르 아부다비", Country: CountryUAE, Category: CategoryMuseum, Offers: []Offer{{"VendorB", 61}, {"VendorC", 67}}},
+	{ProductID: "P031", ProductName: "부르즈 할리파", Country: CountryUAE, Category: CategoryMuseum, Offers: []Offer{{"VendorA", 155}, {"VendorB", 148}, {"VendorC", 161}}},
+	{ProductID: "P032", ProductName: "더 뷰 앳 더 팜", Country: CountryUAE, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 120}, {"VendorC", 125}}},
+	{ProductID: "P033", ProductName: "글로벌 빌리지 두바이", Country: CountryUAE, Category: CategoryAttraction, Offers: []Offer{{"VendorB", 49}}},
+	{ProductID: "P034", ProductName: "미국 자연사 박물관", Country: CountryUSA, Category: CategoryMuseum, Offers: []Offer{{"VendorA", 26}}},
+	{ProductID: "P035", ProductName: "캘리포니아 디즈니", Country: CountryUSA, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 98}, {"VendorC", 102}}},
+	{ProductID: "P036", ProductName: "LA 빅 버스 투어", Country: CountryUSA, Category: CategoryTour, Offers: []Offer{{"VendorB", 41}}},
+	{ProductID: "P037", ProductName: "MoMA 현대 미술관", Country: CountryUSA, Category: CategoryMuseum, Offers: []Offer{{"VendorC", 28}}},
+	{ProductID: "P038", ProductName: "탑 오브 더 락", Country: CountryUSA, Category: CategoryAttraction, Offers: []Offer{{"VendorA", 38}, {"VendorB", 36}}},
 }
@@ -2,14 +2,20 @@ package generator
 
 import (
 	"event-generator/internal/fsm"
-	"math/rand/v2" // v1 대신 v2를 사용합니다.
+	"fmt"
+	"math"
+	"math/rand/v2"
 )
 
 // genPurchase
 func (g *PayloadGenerator) genPurchase(session fsm.Session, eventType string) map[string]any {
+	r := session.Rand()
 	payload := map[string]any{}
 
 	// 1. 세션에서 상세 페이지(Click) 단계 때 저장했던 정보들 가져오기
+	if eventType == string(fsm.EventPurchased) && buyNow(session) {
+		g.ensurePicked(session, r)
+	}
 	lastProductID, lastCategory, lastCountry := session.GetLastPicked()
 	lastQuantity := session.GetLastQuantity()
 
@@ -21,21 +27,108 @@ func (g *PayloadGenerator) genPurchase(session fsm.Session, eventType string) ma
 
 	// 3. 결제 특화 정보
 	paymentMethods := []string{"card", "kakao_pay", "naver_pay", "apple_pay", "google_pay"}
-	// [수정] g.rnd.Intn -> rand.IntN (v2 전역 함수 사용)
-	payload["payment_method"] = paymentMethods[rand.IntN(len(paymentMethods))]
+	payload["payment_method"] = paymentMethods[r.IntN(len(paymentMethods))]
 
 	// 4. 체류 시간
-	// [수정] g.rnd.Intn -> rand.IntN
-	payload["stay_sec"] = rand.IntN(40) + 20
+	payload["stay_sec"] = r.IntN(40) + 20
 
 	// 5. 이벤트 타입별 추가 처리
 	switch eventType {
+	case string(fsm.EventPurchased):
+		// 상세 페이지에서 바로 구매하면 수량이 정해지지 않았으므로 여기서 결정
+		if session.GetPrevState() == fsm.StateClick && lastQuantity <= 0 {
+			lastQuantity = r.IntN(5) + 1
+			session.SetLastQuantity(lastQuantity)
+			payload["quantity"] = lastQuantity
+		}
+		for k, v := range g.checkout(session, r) {
+			payload[k] = v
+		}
+
 	case string(fsm.EventExit):
 		payload["action"] = "order_complete_exit"
 		payload["exit_reason"] = "user_closed_after_purchase"
-		// [수정] g.rnd.Intn -> rand.IntN
-		payload["stay_sec"] = rand.IntN(10) + 2
+		payload["stay_sec"] = r.IntN(10) + 2
 	}
 
 	return payload
 }
+
+// checkout : 결제 대상 줄을 확정하고 주문 정보를 만듭니다.
+// 상세 페이지에서 바로 구매하면 그 상품 한 줄, 장바구니에서 구매하면 장바구니 전체를 결제하고 비웁니다.
+//
+// 주문 단위 값: order_id, purchase_source, line_count, currency, subtotal, discount, total_amount
+// vendor / unit_price 는 한 줄 주문에만 기록합니다. 여러 줄이면 줄별 판매처 / 단가는 attributes.items 에 있습니다.
+// 환율 변환은 하지 않으므로, 통화가 섞인 장바구니는 currency=MIXED 로 total_amount 없이
+// total_by_currency (통화별 할인 후 합계) 와 discount_rate 만 기록합니다.
+func (g *PayloadGenerator) checkout(session fsm.Session, r *rand.Rand) map[string]any {
+	source := "cart_checkout"
+	lines := session.GetCart()
+	if buyNow(session) {
+		source = "buy_now"
+		lines = nil
+		if line, ok := g.currentLine(session, session.GetLastQuantity()); ok {
+			lines = append(lines, line)
+		}
+	}
+
+	session.SetLastOrder(lines)
+	if source == "cart_checkout" {
+		session.SetCart(nil)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	payload := map[string]any{
+		"order_id":        fmt.Sprintf("ord-%d-%06d", session.GetLastEventTs(), r.IntN(1_000_000)),
+		"purchase_source": source,
+		"line_count":      len(lines),
+	}
+
+	subtotals := map[string]int{}
+	for _, l := range lines {
+		subtotals[l.Currency] += l.Subtotal()
+	}
+	rate := pickDiscountRate(r)
+	if len(subtotals) > 1 {
+		totals := make(map[string]int, len(subtotals))
+		for cur, sub := range subtotals {
+			totals[cur] = sub - int(math.Round(float64(sub)*rate))
+		}
+		payload["currency"] = "MIXED"
+		payload["discount_rate"] = rate
+		payload["total_by_currency"] = totals
+		return payload
+	}
+
+	first := lines[0]
+	subtotal := subtotals[first.Currency]
+	discount := int(math.Round(float64(subtotal) * rate))
+	if len(lines) == 1 {
+		payload["vendor"] = first.Vendor
+		payload["unit_price"] = first.UnitPrice
+	}
+	payload["currency"] = first.Currency
+	payload["subtotal"] = subtotal
+	payload["discount"] = discount
+	payload["total_amount"] = subtotal - discount
+	return payload
+}
+
+// buyNow : 상세 페이지에서 바로 구매하거나 장바구니가 비어 있으면 현재 상품 한 줄만 결제합니다.
+func buyNow(session fsm.Session) bool {
+	return session.GetPrevState() == fsm.StateClick || len(session.GetCart()) == 0
+}
+
+// pickDiscountRate : 쿠폰 미사용 70% / 5% 쿠폰 20% / 10% 쿠폰 10%
+func pickDiscountRate(r *rand.Rand) float64 {
+	switch n := r.IntN(100); {
+	case n < 70:
+		return 0
+	case n < 90:
+		return 0.05
+	default:
+		return 0.10
+	}
+}
@@ -3,11 +3,21 @@ package generator
 import (
 	"event-generator/internal/fsm"
 	"log"
-	"math/rand/v2" // v1 대신 v2를 사용합니다.
 )
 
 // genSearch
 func (g *PayloadGenerator) genSearch(session fsm.Session, eventType string) map[string]any {
+	r := session.Rand()
+
+	// 검색 제출 시 카탈로그의 검색어 중 하나를 세션에 저장 (30% 는 선호 여행지 국가명으로 검색)
+	if eventType == string(fsm.EventSearchSubmitted) {
+		keyword := g.preferredDestination(session, r, 0.3)
+		if keyword == "" {
+			keyword = g.catalog.RandomKeyword(r)
+		}
+		session.SetSearchKeyword(keyword)
+	}
+
 	payload := map[string]any{
 		"query": session.GetSearchKeyword(),
 	}
@@ -16,29 +26,26 @@ func (g *PayloadGenerator) genSearch(session fsm.Session, eventType string) map[
 
 	case string(fsm.EventPageViewed):
 		// 검색 결과 페이지 탐색
-		// [수정] g.rnd.Intn -> rand.IntN (v2 전역 함수 사용)
-		payload["stay_sec"] = rand.IntN(40) + 1
+		payload["stay_sec"] = r.IntN(40) + 1
 
 	case string(fsm.EventBack):
 		// 홈으로 뒤로가기
 		payload["exit_reason"] = "back_to_home"
-		// [수정] g.rnd.Intn -> rand.IntN
-		payload["stay_sec"] = rand.IntN(5) + 1
+		payload["stay_sec"] = r.IntN(5) + 1
 
 	case string(fsm.EventExit):
 		// 검색 이탈
 		payload["exit_reason"] = "search_exit"
-		// [수정] g.rnd.Intn -> rand.IntN
-		payload["stay_sec"] = rand.IntN(5) + 1
+		payload["stay_sec"] = r.IntN(5) + 1
 
 	case string(fsm.EventProductClicked):
 		keyword := session.GetSearchKeyword()
 		// 키워드로 상품 구분 및 획득
-		product, _ := DistinguishAndGetProduct(keyword)
+		product, _ := g.catalog.Distinguish(r, keyword)
 
 		if product != nil {
 			// 세션에 저장
-			session.SetLastPicked(product.ProductID, product.Category, product.Country)
+			rememberProduct(session, r, product)
 
 			// 3. 페이로드 구성
 			payload["product_id"] = product.ProductID
@@ -1,122 +1,57 @@
 package generator
 
 import (
-	"fmt"
-	"log"
-	"math/rand/v2" // v1 대신 v2를 사용합니다. (Thread-safe 최적화)
+	"event-generator/internal/fsm"
+	"math/rand/v2"
 )
 
-// 검색어 세팅용 함수
-// [수정] 인자에서 g.rnd 의존성 제거
-func (g *PayloadGenerator) generateKeyword() string {
-	keywords := []string{
-		"홍콩", "대만", "마카오", "싱가포르", "말레이시아", "태국", "UAE", "미국",
-		"콤보 상품",
-		"홍콩 디즈니", "코타이젯", "홍콩 터보젯", "피크트램", "옹핑 케이블카",
-		"국립박물관", "딘 타이 펑", "타이페이 101", "Easy 심카드",
-		"마카오 오픈 탑 버스", "마카오 해리 포터", "마카오 터보젯", "타워 360", "마카오 전망대",
-		"가든스 바이 더 베이", "유니버셜 스튜디오 싱가포르", "윙스 오브 타임",
-		"싱가포르 플라이어", "리버크루즈", "나이트 사파리",
-		"레고랜드", "슈퍼파크 말레이시아", "썬웨이 라군",
-		"5G 심카드", "진리의 성전", "마하나콘 전망대", "푸켓 아쿠아리움",
-		"카스르 알 와탄", "페라리 월드 아부다비", "루브르 아부다비",
-		"부르즈 할리파", "더 뷰 앳 더 팜", "글로벌 빌리지 두바이",
-		"미국 자연사 박물관", "캘리포니아 디즈니",
-		"LA 빅 버스", "MoMA", "탑 오브 더 락",
+// rememberProduct : 선택한 상품과 판매처(Offers 중 하나)를 세션에 저장합니다.
+// 이후 장바구니/구매 이벤트는 같은 판매처로 기록됩니다.
+// 가격에 민감한 유저일수록 최저가 판매처를 고를 확률이 높습니다.
+func rememberProduct(session fsm.Session, r *rand.Rand, p *Product) {
+	session.SetLastPicked(p.ProductID, p.Category, p.Country)
+
+	vendor := ""
+	if len(p.Offers) > 0 {
+		if r.Float64() < session.GetPriceSensitivity() {
+			vendor = cheapestOffer(p.Offers).Vendor
+		} else {
+			vendor = p.Offers[r.IntN(len(p.Offers))].Vendor
+		}
 	}
-
-	// [수정] rand.Intn -> rand.IntN (v2 전역 함수)
-	return keywords[rand.IntN(len(keywords))]
-}
-
-// 국가별 상품 풀 (기존 동일)
-var countryProductPool = map[string][]string{
-	"홍콩":    {"홍콩 디즈니", "코타이젯", "홍콩 터보젯", "피크트램", "옹핑 케이블카"},
-	"대만":    {"대만 국립박물관", "딘 타이 펑", "타이페이 101", "Easy 심카드"},
-	"마카오":   {"마카오 오픈 탑 버스", "마카오 해리 포터", "마카오 터보젯", "타워 360", "마카오 전망대"},
-	"싱가포르":  {"가든스 바이 더 베이", "유니버셜 스튜디오 싱가포르", "윙스 오브 타임", "싱가포르 플라이어", "리버크루즈", "나이트 사파리"},
-	"말레이시아": {"레고랜드", "슈퍼파크 말레이시아", "썬웨이 라군"},
-	"태국":    {"5G 심카드", "진리의 성전", "마하나콘 전망대", "푸켓 아쿠아리움"},
-	"UAE":   {"카스르 알 와탄", "페라리 월드 아부다비", "루브르 아부다비", "부르즈 할리파", "더 뷰 앳 더 팜", "글로벌 빌리지 두바이"},
-	"미국":    {"미국 자연사 박물관", "캘리포니아 디즈니", "LA 빅 버스", "MoMA", "탑 오브 더 락"},
+	session.SetLastVendor(vendor)
 }
 
-func (g *PayloadGenerator) pickTopCountryProducts(country string, n int) []string {
-	pool := countryProductPool[country]
-	if len(pool) == 0 {
-		return nil
+// ensurePicked : 세션에 선택한 상품이 없으면 (카테고리 / 검색에서 상품을 찾지 못한 경우 등) 홈 노출 상품 하나를 골라 기억합니다.
+// 장바구니 담기 / 구매는 항상 대상 상품이 있어야 하므로 그 직전에 호출합니다.
+func (g *PayloadGenerator) ensurePicked(session fsm.Session, r *rand.Rand) {
+	productID, _, _ := session.GetLastPicked()
+	if _, ok := g.catalog.ProductByID(productID); ok {
+		return
 	}
-
-	if n >= len(pool) {
-		return append([]string{}, pool...)
-	}
-
-	return append([]string{}, pool[:n]...)
+	rememberProduct(session, r, g.catalog.RandomHomeProduct(r))
 }
 
-type PickedProduct struct {
-	Product Product
-	Vendor  string
-}
-
-// 홈 상단 노출 대상 상품 ID
-var homeExposureProductIDs = []string{
-	"P001", // 홍콩 디즈니
-	"P016", // 유니버셜 스튜디오 싱가포르
-	"P022", // 슈퍼파크 말레이시아
-	"P029", // 페라리 월드 아부다비
-	"P035", // 캘리포니아 디즈니
-}
-
-// [수정] 인자에서 r *rand.Rand 제거 및 v2 적용
-func pickTopExposureProduct() Product {
-	// [수정] rand.IntN 사용
-	pid := homeExposureProductIDs[rand.IntN(len(homeExposureProductIDs))]
-
-	for _, p := range products {
-		if p.ProductID == pid {
-			return p
+func cheapestOffer(offers []Offer) Offer {
+	best := offers[0]
+	for _, o := range offers[1:] {
+		if o.Price < best.Price {
+			best = o
 		}
 	}
-
-	panic("home exposure product not found: " + pid)
+	return best
 }
 
-// 국가명에 맞는 상품을 랜덤으로 선택하는 함수
-func pickProductByCountry(country string) (Product, error) {
-	var candidates []Product
-
-	for _, p := range products {
-		if p.Country == country {
-			candidates = append(candidates, p)
-		}
-	}
-
-	if len(candidates) == 0 {
-		log.Println("No product found for the given country:", country)
-		return Product{}, fmt.Errorf("No products found for country: %s", country)
-	}
-
-	// [수정] rand.Seed 제거 (v2에서는 필요 없으며 성능 저하 원인임)
-	// [수정] rand.IntN 사용
-	return candidates[rand.IntN(len(candidates))], nil
-}
-
-// 상품 카테고리에 맞는 상품을 랜덤으로 선택하는 함수
-func pickProductByCategory(category string) (Product, error) {
-	var candidates []Product
-
-	for _, p := range products {
-		if p.Category == category {
-			candidates = append(candidates, p)
-		}
+// preferredDestination : 유저의 선호 여행지 중 카탈로그에 있는 국가를 p 확률로 고릅니다.
+// 고르지 않았거나 후보가 없으면 빈 문자열을 반환합니다.
+func (g *PayloadGenerator) preferredDestination(session fsm.Session, r *rand.Rand, p float64) string {
+	dests := session.GetPreferredDestinations()
+	if len(dests) == 0 || r.Float64() >= p {
+		return ""
 	}
-
-	if len(candidates) == 0 {
-		log.Println("No product found for the given category:", category)
-		return Product{}, fmt.Errorf("No products found for category: %s", category)
+	country := dests[r.IntN(len(dests))]
+	if _, ok := g.catalog.RandomProductByCountry(r, country); !ok {
+		return ""
 	}
-
-	// [수정] rand.IntN 사용
-	return candidates[rand.IntN(len(candidates))], nil
+	return country
 }
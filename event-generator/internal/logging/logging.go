@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// =======================
+// Diagnostic log
+// =======================
+
+// 진단 로그 ([MAIN], [LoadController] 같은 태그를 붙인 한 줄 로그) 의 출력 대상
+// 기본은 표준출력이며, stdout Sink 가 표준출력을 이벤트 스트림으로 쓰면 cmd/generator 가 SetOutput 으로 stderr 로 돌립니다.
+// 여러 고루틴의 로그가 한 줄 안에서 섞이지 않도록 쓰기를 뮤텍스로 묶습니다.
+var (
+	mu  sync.Mutex
+	out io.Writer = os.Stdout
+)
+
+// SetOutput : 이후의 진단 로그를 w 로 보냅니다.
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	out = w
+}
+
+// Writer : 현재 출력 대상 (여러 줄을 한 번에 쓰는 설정 출력 등에 사용)
+func Writer() io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return out.Write(p)
+	})
+}
+
+func Printf(format string, args ...any) {
+	mu.Lock()
+	defer mu.Unlock()
+	fmt.Fprintf(out, format, args...)
+}
+
+func Println(args ...any) {
+	mu.Lock()
+	defer mu.Unlock()
+	fmt.Fprintln(out, args...)
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -1,8 +1,10 @@
 package metrics
 
 import (
+	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // InMemoryMetrics 는 Metrics 인터페이스를 구현하며
@@ -15,11 +17,48 @@ type InMemoryMetrics struct {
 	eventsByType     sync.Map
 	stateTransitions sync.Map
 	errorsByType     sync.Map
+	sessionsByEnd    sync.Map
+
+	eventsByPersona      sync.Map
+	sessionsByPersona    sync.Map
+	conversionsByPersona sync.Map
+
+	delivered            atomic.Int64
+	deliveryFailed       atomic.Int64
+	retries              atomic.Int64
+	deliveredByPartition sync.Map
+	failedByPartition    sync.Map
+	retriesByTopic       sync.Map
+	produceLatency       *Histogram
+
+	channelFull     atomic.Int64
+	droppedByPolicy sync.Map
+	spilled         atomic.Int64
 }
 
 // NewInMemory 초기화
 func NewInMemory() *InMemoryMetrics {
-	return &InMemoryMetrics{}
+	return &InMemoryMetrics{
+		produceLatency: NewHistogram(DefaultLatencyBuckets),
+	}
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
+
+func addTo(m *sync.Map, key string, n int64) {
+	val, _ := m.LoadOrStore(key, &atomic.Int64{})
+	val.(*atomic.Int64).Add(n)
+}
+
+func loadAll(m *sync.Map) map[string]int64 {
+	out := make(map[string]int64)
+	m.Range(func(k, v any) bool {
+		out[k.(string)] = v.(*atomic.Int64).Load()
+		return true
+	})
+	return out
 }
 
 // =======================
@@ -38,9 +77,10 @@ func (m *InMemoryMetrics) IncSessionStart() {
 	m.sessionsStarted.Add(1)
 }
 
-// 세션 완료 카운트
-func (m *InMemoryMetrics) IncSessionComplete() {
+// 세션 종료 카운트 (종료 사유별)
+func (m *InMemoryMetrics) IncSessionEnd(reason string) {
 	m.sessionsComplete.Add(1)
+	addTo(&m.sessionsByEnd, reason, 1)
 }
 
 // 상태 전환 카운트
@@ -56,6 +96,62 @@ func (m *InMemoryMetrics) IncError(errorType string) {
 	val.(*atomic.Int64).Add(1)
 }
 
+// 페르소나별 이벤트 카운트
+func (m *InMemoryMetrics) IncPersonaEvent(persona string) {
+	addTo(&m.eventsByPersona, persona, 1)
+}
+
+// 페르소나별 세션 시작 카운트
+func (m *InMemoryMetrics) IncPersonaSession(persona string) {
+	addTo(&m.sessionsByPersona, persona, 1)
+}
+
+// 페르소나별 구매 전환 카운트
+func (m *InMemoryMetrics) IncPersonaConversion(persona string) {
+	addTo(&m.conversionsByPersona, persona, 1)
+}
+
+// 전송 확정(ack) 카운트
+func (m *InMemoryMetrics) IncDelivered(topic string, partition int, n int) {
+	m.delivered.Add(int64(n))
+	addTo(&m.deliveredByPartition, PartitionKey(topic, partition), int64(n))
+}
+
+// 전송 실패 카운트 (재시도 소진 후 최종 실패)
+func (m *InMemoryMetrics) IncDeliveryFailed(topic string, partition int, n int) {
+	m.deliveryFailed.Add(int64(n))
+	addTo(&m.failedByPartition, PartitionKey(topic, partition), int64(n))
+}
+
+// 재시도 카운트
+func (m *InMemoryMetrics) IncRetries(topic string, n int64) {
+	if n == 0 {
+		return
+	}
+	m.retries.Add(n)
+	addTo(&m.retriesByTopic, topic, n)
+}
+
+// 적재 → ack 까지 걸린 시간
+func (m *InMemoryMetrics) ObserveProduceLatency(d time.Duration) {
+	m.produceLatency.Observe(d.Seconds())
+}
+
+// 이벤트 채널이 가득 찬 횟수
+func (m *InMemoryMetrics) IncChannelFull() {
+	m.channelFull.Add(1)
+}
+
+// backpressure 정책으로 버린 이벤트 카운트
+func (m *InMemoryMetrics) IncDropped(policy string, n int) {
+	addTo(&m.droppedByPolicy, policy, int64(n))
+}
+
+// 디스크 큐 적재 카운트
+func (m *InMemoryMetrics) IncSpilled(n int) {
+	m.spilled.Add(int64(n))
+}
+
 // =======================
 // Snapshot
 // =======================
@@ -85,5 +181,22 @@ func (m *InMemoryMetrics) Snapshot() Snapshot {
 		return true
 	})
 
+	snap.SessionsByEnd = loadAll(&m.sessionsByEnd)
+	snap.EventsByPersona = loadAll(&m.eventsByPersona)
+	snap.SessionsByPersona = loadAll(&m.sessionsByPersona)
+	snap.ConversionsByPersona = loadAll(&m.conversionsByPersona)
+
+	snap.Delivered = m.delivered.Load()
+	snap.DeliveryFailed = m.deliveryFailed.Load()
+	snap.Retries = m.retries.Load()
+	snap.DeliveredByPartition = loadAll(&m.deliveredByPartition)
+	snap.FailedByPartition = loadAll(&m.failedByPartition)
+	snap.RetriesByTopic = loadAll(&m.retriesByTopic)
+	snap.ProduceLatency = m.produceLatency.Snapshot()
+
+	snap.ChannelFull = m.channelFull.Load()
+	snap.DroppedByPolicy = loadAll(&m.droppedByPolicy)
+	snap.Spilled = m.spilled.Load()
+
 	return snap
 }
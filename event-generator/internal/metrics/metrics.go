@@ -1,19 +1,67 @@
 package metrics
 
+import "time"
+
 type Metrics interface {
 	IncEvent(eventType string)
+	// 세션 지표는 session_start / session_end 생명주기 이벤트를 내보낼 때 기록
 	IncSessionStart()
-	IncSessionComplete()
+	IncSessionEnd(reason string)
 	IncStateTransition(prev, next string)
 	IncError(errorType string)
+
+	// 페르소나별 생성 이벤트 / 세션 / 구매 전환
+	IncPersonaEvent(persona string)
+	IncPersonaSession(persona string)
+	IncPersonaConversion(persona string)
+
+	// 전송 확정(delivery report) 기록
+	IncDelivered(topic string, partition int, n int)
+	IncDeliveryFailed(topic string, partition int, n int)
+	IncRetries(topic string, n int64)
+	ObserveProduceLatency(d time.Duration)
+
+	// 이벤트 채널 backpressure (채널이 가득 찼을 때 정책별 처리)
+	IncChannelFull()
+	IncDropped(policy string, n int)
+	IncSpilled(n int)
+
 	Snapshot() Snapshot
 }
 
 type Snapshot struct {
-	TotalEvents      int64
+	TotalEvents      int64 // Sink 에 기록(Kafka 는 내부 큐에 적재)된 이벤트 수
 	EventsByType     map[string]int64
 	SessionsStarted  int64
 	SessionsComplete int64
+	SessionsByEnd    map[string]int64 // 종료 사유별 세션 수 (exit / purchase_exit / timeout)
 	StateTransitions map[string]int64
 	ErrorsByType     map[string]int64
+
+	// 페르소나별 지표 (SessionManager 가 생성 시점에 기록)
+	EventsByPersona      map[string]int64
+	SessionsByPersona    map[string]int64
+	ConversionsByPersona map[string]int64 // purchased 이벤트 수
+
+	// 전송 확정 지표 (key: "topic/partition")
+	Delivered            int64
+	DeliveryFailed       int64
+	Retries              int64
+	DeliveredByPartition map[string]int64
+	FailedByPartition    map[string]int64
+	RetriesByTopic       map[string]int64
+	ProduceLatency       HistogramSnapshot // 초 단위
+
+	// backpressure 지표
+	ChannelFull     int64            // 이벤트를 넣으려 할 때 채널이 가득 차 있던 횟수
+	DroppedByPolicy map[string]int64 // 정책별 버린 이벤트 수
+	Spilled         int64            // 디스크 큐에 적재한 이벤트 수
+}
+
+// PartitionKey : 파티션별 지표 키 ("topic/partition")
+func PartitionKey(topic string, partition int) string {
+	if partition < 0 {
+		return topic + "/unknown"
+	}
+	return topic + "/" + itoa(partition)
 }
@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// =======================
+// Prometheus Exporter
+// =======================
+
+// GaugeFunc 는 스크레이프 시점에 값을 읽어 오는 게이지입니다.
+// (활성 세션 수, 채널 적체량처럼 Metrics 밖에서 관리되는 값)
+type GaugeFunc struct {
+	Name string
+	Help string
+	Fn   func() float64
+}
+
+// NewPrometheusHandler : Metrics 스냅샷과 게이지를 Prometheus text format(0.0.4)으로 노출하는 핸들러
+func NewPrometheusHandler(m Metrics, gauges ...GaugeFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		bw := bufio.NewWriter(w)
+		defer bw.Flush()
+
+		snap := m.Snapshot()
+
+		writeCounter(bw, "eventgen_events_total", "Total number of events written to the sink.", snap.TotalEvents)
+		writeLabeledCounter(bw, "eventgen_events_by_type_total", "Events written to the sink by event type.",
+			snap.EventsByType, func(k string) string { return label("event_type", k) })
+		writeLabeledCounter(bw, "eventgen_state_transitions_total", "FSM state transitions.",
+			snap.StateTransitions, transitionLabels)
+		writeLabeledCounter(bw, "eventgen_errors_total", "Errors by type.",
+			snap.ErrorsByType, func(k string) string { return label("type", k) })
+		writeCounter(bw, "eventgen_sessions_started_total", "Sessions started.", snap.SessionsStarted)
+		writeCounter(bw, "eventgen_sessions_completed_total", "Sessions completed or expired.", snap.SessionsComplete)
+		writeLabeledCounter(bw, "eventgen_sessions_ended_total", "Sessions ended by session_end reason.",
+			snap.SessionsByEnd, func(k string) string { return label("reason", k) })
+		writeLabeledCounter(bw, "eventgen_persona_events_total", "Events generated by user persona.",
+			snap.EventsByPersona, func(k string) string { return label("persona", k) })
+		writeLabeledCounter(bw, "eventgen_persona_sessions_total", "Sessions started by user persona.",
+			snap.SessionsByPersona, func(k string) string { return label("persona", k) })
+		writeLabeledCounter(bw, "eventgen_persona_conversions_total", "Purchases by user persona.",
+			snap.ConversionsByPersona, func(k string) string { return label("persona", k) })
+
+		writeLabeledCounter(bw, "eventgen_delivered_total", "Messages acknowledged by the sink, by topic and partition.",
+			snap.DeliveredByPartition, partitionLabels)
+		writeLabeledCounter(bw, "eventgen_delivery_failed_total", "Messages that failed delivery after retries, by topic and partition.",
+			snap.FailedByPartition, partitionLabels)
+		writeLabeledCounter(bw, "eventgen_delivery_retries_total", "Produce request retries by topic.",
+			snap.RetriesByTopic, func(k string) string { return label("topic", k) })
+		writeHistogram(bw, "eventgen_produce_latency_seconds", "Time from enqueue to acknowledgement.", snap.ProduceLatency)
+
+		writeCounter(bw, "eventgen_event_channel_full_total", "Times the event channel was full when the SessionManager pushed an event.", snap.ChannelFull)
+		writeLabeledCounter(bw, "eventgen_events_dropped_total", "Events dropped by the backpressure policy.",
+			snap.DroppedByPolicy, func(k string) string { return label("policy", k) })
+		writeCounter(bw, "eventgen_events_spilled_total", "Events spilled to the local disk queue.", snap.Spilled)
+
+		for _, g := range gauges {
+			fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", g.Name, g.Help, g.Name, g.Name, g.Fn())
+		}
+	})
+}
+
+func writeCounter(w *bufio.Writer, name, help string, v int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, v)
+}
+
+func writeLabeledCounter(w *bufio.Writer, name, help string, values map[string]int64, labels func(string) string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s} %d\n", name, labels(k), values[k])
+	}
+}
+
+func writeHistogram(w *bufio.Writer, name, help string, h HistogramSnapshot) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+
+	var acc int64
+	for i, c := range h.Counts {
+		acc += c
+		le := "+Inf"
+		if i < len(h.Bounds) {
+			le = fmt.Sprintf("%g", h.Bounds[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, le, acc)
+	}
+	fmt.Fprintf(w, "%s_sum %g\n%s_count %d\n", name, h.Sum, name, h.Count)
+}
+
+// partitionLabels : "topic/partition" 키를 topic/partition 라벨로 분리
+func partitionLabels(key string) string {
+	i := strings.LastIndex(key, "/")
+	if i < 0 {
+		return label("topic", key)
+	}
+	return label("topic", key[:i]) + "," + label("partition", key[i+1:])
+}
+
+// transitionLabels : "prev -> next" 키를 from/to 라벨로 분리
+func transitionLabels(key string) string {
+	from, to, ok := strings.Cut(key, " -> ")
+	if !ok {
+		return label("transition", key)
+	}
+	return label("from", from) + "," + label("to", to)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func label(name, value string) string {
+	return name + `="` + labelEscaper.Replace(value) + `"`
+}
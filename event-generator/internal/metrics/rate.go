@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateMeter 는 누적 카운터를 주기적으로 샘플링하여 초당 처리량(TPS)을 계산합니다.
+type RateMeter struct {
+	mu       sync.Mutex
+	last     int64
+	lastAt   time.Time
+	rateBits atomic.Uint64
+}
+
+func NewRateMeter() *RateMeter {
+	return &RateMeter{}
+}
+
+// Observe : 현재 누적값을 기록하고 직전 샘플 대비 초당 증가량을 갱신합니다.
+func (r *RateMeter) Observe(total int64, at time.Time) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.lastAt.IsZero() {
+		r.last, r.lastAt = total, at
+		return 0
+	}
+
+	elapsed := at.Sub(r.lastAt).Seconds()
+	if elapsed <= 0 {
+		return r.Rate()
+	}
+
+	rate := float64(total-r.last) / elapsed
+	r.rateBits.Store(math.Float64bits(rate))
+	r.last, r.lastAt = total, at
+	return rate
+}
+
+// Rate : 마지막으로 계산된 초당 처리량
+func (r *RateMeter) Rate() float64 {
+	return math.Float64frombits(r.rateBits.Load())
+}
@@ -0,0 +1,300 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"event-generator/internal/event"
+	"event-generator/internal/logging"
+	"event-generator/internal/metrics"
+)
+
+// =======================
+// Backpressure policy
+// =======================
+
+// 이벤트 채널이 가득 찼을 때의 처리 방식
+const (
+	PolicyBlock      = "block"       // 자리가 날 때까지 생산자(SessionManager.Step)가 대기
+	PolicyDropNewest = "drop_newest" // 새 이벤트를 버림
+	PolicyDropOldest = "drop_oldest" // 채널에서 가장 오래된 이벤트를 버리고 새 이벤트를 넣음
+	PolicySpill      = "spill"       // 로컬 디스크 큐에 적재했다가 자리가 나면 채널로 되돌림
+)
+
+// Policies : 지원하는 정책 이름
+var Policies = []string{PolicyBlock, PolicyDropNewest, PolicyDropOldest, PolicySpill}
+
+// 채널 적체 경고를 다시 출력하기까지의 최소 간격
+const warnInterval = 5 * time.Second
+
+// drop_oldest 에서 자리를 만들기 위해 시도하는 최대 횟수 (워커와 경쟁해 계속 실패하면 새 이벤트를 버림)
+const dropOldestAttempts = 4
+
+// Options : New 에서 사용하는 큐 생성 옵션
+type Options struct {
+	Capacity int
+	Policy   string
+
+	// 채널이 이 비율(0~1) 이상 차면 [BACKPRESSURE] 경고를 출력 (0 이면 끔)
+	WarnRatio float64
+
+	// spill
+	SpillPath string // 디스크 큐 파일 경로 (비어 있으면 임시 디렉터리)
+	SpillMax  int64  // 디스크 큐에 보관할 최대 이벤트 수 (0 이면 무제한, 넘치면 새 이벤트를 버림)
+
+	Metrics metrics.Metrics
+}
+
+// =======================
+// EventQueue
+// =======================
+
+// EventQueue 는 SessionManager → Worker 이벤트 채널을 감싸고
+// 채널이 가득 찼을 때 정책에 따라 대기 / 유실 / 디스크 적재를 합니다.
+// 조용히 처리량이 떨어지는 대신 channel_full / dropped / spilled 카운터와 경고로 병목을 드러냅니다.
+type EventQueue struct {
+	ch      chan *event.Event
+	policy  string
+	metrics metrics.Metrics
+
+	warnAt   int
+	lastWarn atomic.Int64 // 마지막 경고 시각 (unix nanos)
+
+	// spill
+	spill    *spillQueue
+	spillMax int64
+	spillMu  sync.Mutex   // 디스크 큐가 비어 있는지 확인하고 채널에 넣는 과정을 묶어 순서를 보장
+	backlog  atomic.Int64 // 디스크 큐에 적재했지만 아직 채널로 되돌리지 못한 이벤트 수
+	failed   atomic.Bool  // 디스크 큐 읽기 실패: 이후 Push 는 block 정책으로 동작
+	readErr  error        // 디스크 큐 읽기 실패 원인과 유실 수 (refill 종료 전에 기록, Close 에서 반환)
+	wake     chan struct{}
+	closing  chan struct{} // Close 시작: 디스크 큐를 모두 되돌리면 refill 종료
+	abort    chan struct{} // Close 기한 초과: 남은 이벤트를 두고 즉시 종료
+	stopped  chan struct{}
+}
+
+// New : 정책에 맞는 이벤트 큐를 만듭니다. spill 정책이면 디스크 큐 파일을 열고 되돌리는 고루틴을 시작합니다.
+func New(opts Options) (*EventQueue, error) {
+	q := &EventQueue{
+		ch:      make(chan *event.Event, opts.Capacity),
+		policy:  opts.Policy,
+		metrics: opts.Metrics,
+	}
+	if opts.WarnRatio > 0 {
+		q.warnAt = max(int(float64(opts.Capacity)*opts.WarnRatio), 1)
+	}
+
+	switch opts.Policy {
+	case PolicyBlock, PolicyDropNewest, PolicyDropOldest:
+	case PolicySpill:
+		sq, err := openSpill(opts.SpillPath)
+		if err != nil {
+			return nil, err
+		}
+		q.spill = sq
+		q.spillMax = opts.SpillMax
+		q.wake = make(chan struct{}, 1)
+		q.closing = make(chan struct{})
+		q.abort = make(chan struct{})
+		q.stopped = make(chan struct{})
+		go q.refill()
+	default:
+		return nil, fmt.Errorf("unknown backpressure policy: %q", opts.Policy)
+	}
+	return q, nil
+}
+
+// C : Worker 가 이벤트를 꺼내는 채널
+func (q *EventQueue) C() <-chan *event.Event {
+	return q.ch
+}
+
+// Len : 채널에 쌓인 이벤트 수
+func (q *EventQueue) Len() int {
+	return len(q.ch)
+}
+
+// Cap : 채널 용량
+func (q *EventQueue) Cap() int {
+	return cap(q.ch)
+}
+
+// Spilled : 디스크 큐에 남아 있는 이벤트 수
+func (q *EventQueue) Spilled() int64 {
+	return q.backlog.Load()
+}
+
+// Pending : 아직 Worker 가 꺼내지 않은 이벤트 수 (채널 + 디스크 큐)
+func (q *EventQueue) Pending() int64 {
+	return int64(len(q.ch)) + q.Spilled()
+}
+
+// Push : 이벤트를 큐에 넣습니다. 채널이 가득 차면 정책에 따라 처리합니다.
+func (q *EventQueue) Push(ev *event.Event) {
+	q.checkWarn()
+
+	if q.policy == PolicySpill && q.pushSpill(ev) {
+		return
+	}
+
+	select {
+	case q.ch <- ev:
+		return
+	default:
+	}
+	q.metrics.IncChannelFull()
+
+	switch q.policy {
+	case PolicyDropNewest:
+		q.metrics.IncDropped(PolicyDropNewest, 1)
+
+	case PolicyDropOldest:
+		for i := 0; i < dropOldestAttempts; i++ {
+			select {
+			case <-q.ch:
+				q.metrics.IncDropped(PolicyDropOldest, 1)
+			default:
+			}
+			select {
+			case q.ch <- ev:
+				return
+			default:
+			}
+		}
+		q.metrics.IncDropped(PolicyDropOldest, 1)
+
+	default:
+		q.ch <- ev
+	}
+}
+
+// pushSpill : 디스크 큐가 비어 있고 채널에 자리가 있으면 채널로, 아니면 디스크 큐 뒤에 적재합니다.
+// 디스크 큐에 이벤트가 남아 있는 동안은 순서를 지키기 위해 새 이벤트도 디스크 큐로 보냅니다.
+// 디스크 큐 읽기가 실패한 뒤에는 처리하지 않고 false 를 반환합니다 (Push 가 block 정책으로 처리).
+func (q *EventQueue) pushSpill(ev *event.Event) bool {
+	q.spillMu.Lock()
+	defer q.spillMu.Unlock()
+
+	if q.failed.Load() {
+		return false
+	}
+
+	if q.backlog.Load() == 0 {
+		select {
+		case q.ch <- ev:
+			return true
+		default:
+			q.metrics.IncChannelFull()
+		}
+	}
+
+	if q.spillMax > 0 && q.backlog.Load() >= q.spillMax {
+		q.metrics.IncDropped(PolicySpill, 1)
+		return true
+	}
+	if err := q.spill.push(ev); err != nil {
+		q.metrics.IncError("spill_write")
+		q.metrics.IncDropped(PolicySpill, 1)
+		return true
+	}
+	q.backlog.Add(1)
+	q.metrics.IncSpilled(1)
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// refill : 디스크 큐의 이벤트를 순서대로 채널에 되돌립니다.
+// Close 가 시작되면 디스크 큐가 빌 때까지 되돌린 뒤 종료합니다.
+// 디스크 큐를 읽지 못하면 남은 이벤트를 유실로 집계하고 종료하며, 이후 Push 는 block 정책으로 동작합니다.
+func (q *EventQueue) refill() {
+	defer close(q.stopped)
+
+	for {
+		if q.spill.Len() == 0 {
+			select {
+			case <-q.wake:
+				continue
+			case <-q.closing:
+				return
+			}
+		}
+
+		q.spillMu.Lock()
+		batch, err := q.spill.pop(spillBatch)
+		if err != nil {
+			q.failSpill(err)
+			q.spillMu.Unlock()
+			return
+		}
+		q.spillMu.Unlock()
+		for _, ev := range batch {
+			select {
+			case q.ch <- ev:
+				q.backlog.Add(-1)
+			case <-q.abort:
+				return
+			}
+		}
+	}
+}
+
+// failSpill : 디스크 큐 읽기 실패 처리 (spillMu 를 잡은 상태에서 호출)
+// 되돌리지 못한 이벤트를 spill 정책 유실로 집계하고 backlog 를 비워, 채널에 이벤트가 남아 있다고 잘못 보고하지 않게 합니다.
+func (q *EventQueue) failSpill(err error) {
+	lost := q.backlog.Swap(0)
+	q.failed.Store(true)
+	q.readErr = fmt.Errorf("spill queue read failed, %d spilled events lost: %w", lost, err)
+	q.metrics.IncError("spill_read")
+	q.metrics.IncDropped(PolicySpill, int(lost))
+	logging.Printf("[BACKPRESSURE] spill read error: %v - %d spilled events lost, falling back to the block policy\n", err, lost)
+}
+
+// checkWarn : 채널이 경고 비율 이상 차 있으면 warnInterval 마다 한 번 경고를 출력합니다.
+func (q *EventQueue) checkWarn() {
+	if q.warnAt == 0 {
+		return
+	}
+	n := len(q.ch)
+	if n < q.warnAt {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := q.lastWarn.Load()
+	if now-last < int64(warnInterval) || !q.lastWarn.CompareAndSwap(last, now) {
+		return
+	}
+	logging.Printf("[BACKPRESSURE] event channel %d/%d (%.0f%%) full, policy=%s, spilled=%d - producers are outpacing the sink\n",
+		n, cap(q.ch), 100*float64(n)/float64(cap(q.ch)), q.policy, q.Spilled())
+}
+
+// Close : 더 이상 Push 하지 않을 때 (생산자를 모두 멈춘 뒤) 호출합니다.
+// 디스크 큐의 이벤트를 모두 채널로 되돌린 다음 채널을 닫으므로, Worker 는 남은 이벤트를 끝까지 꺼낸 뒤 종료합니다.
+// ctx 가 먼저 끝나면 되돌리기를 멈추고 채널을 닫으며, 되돌리지 못한 이벤트 수를 반환합니다.
+// 실행 중 디스크 큐 읽기가 실패했다면 유실한 이벤트 수를 담은 오류를 함께 반환합니다.
+// 디스크 큐 파일은 닫고 임시 파일이면 삭제합니다.
+func (q *EventQueue) Close(ctx context.Context) (int64, error) {
+	if q.spill == nil {
+		close(q.ch)
+		return 0, nil
+	}
+
+	close(q.closing)
+	var err error
+	select {
+	case <-q.stopped:
+	case <-ctx.Done():
+		close(q.abort)
+		<-q.stopped
+		err = fmt.Errorf("spill queue drain: %w", ctx.Err())
+	}
+	close(q.ch)
+	return q.backlog.Load(), errors.Join(err, q.readErr, q.spill.close())
+}
@@ -0,0 +1,66 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"event-generator/internal/event"
+	"event-generator/internal/metrics"
+)
+
+// TestSpillReadFailure : 디스크 큐를 읽지 못하면 남은 이벤트를 유실로 집계하고, 이후 Push 는 block 정책으로 전달하며,
+// Close 가 유실 수를 오류로 보고하는지 확인합니다.
+func TestSpillReadFailure(t *testing.T) {
+	const pushed = 200
+
+	m := metrics.NewInMemory()
+	q, err := New(Options{Capacity: 1, Policy: PolicySpill, Metrics: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 디스크 큐 파일을 미리 닫아 첫 읽기 (Flush) 부터 실패하게 함
+	q.spill.f.Close()
+
+	// 읽기 실패 전에는 채널을 비우지 않아 이벤트가 디스크 큐에 쌓이게 함
+	received := make(chan int)
+	go func() {
+		for !q.failed.Load() {
+			time.Sleep(time.Millisecond)
+		}
+		n := 0
+		for range q.C() {
+			n++
+		}
+		received <- n
+	}()
+
+	for i := range pushed {
+		q.Push(&event.Event{EventID: fmt.Sprintf("evt-%d", i)})
+	}
+	left, err := q.Close(context.Background())
+	n := <-received
+
+	if err == nil || !strings.Contains(err.Error(), "spill queue read failed") {
+		t.Fatalf("Close error = %v, want a spill read failure", err)
+	}
+	if left != 0 {
+		t.Fatalf("Close reported %d events left in the spill queue, want 0 after the failure", left)
+	}
+	snap := m.Snapshot()
+	lost := snap.DroppedByPolicy[PolicySpill]
+	if lost == 0 {
+		t.Fatal("no spilled events were counted as dropped")
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("%d spilled events lost", lost)) {
+		t.Fatalf("Close error %q does not report the %d lost events", err, lost)
+	}
+	if snap.ErrorsByType["spill_read"] != 1 {
+		t.Fatalf("spill_read errors = %d, want 1", snap.ErrorsByType["spill_read"])
+	}
+	if got := int64(n) + lost; got != pushed {
+		t.Fatalf("received %d + lost %d = %d, want every pushed event (%d) accounted for", n, lost, got, pushed)
+	}
+}
@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"event-generator/internal/event"
+)
+
+// 디스크 큐에서 한 번에 읽어 채널로 되돌리는 이벤트 수
+const spillBatch = 500
+
+// =======================
+// Spill queue
+// =======================
+
+// spillQueue : NDJSON 파일 하나를 사용하는 FIFO 디스크 큐
+// 뒤에 추가하고 앞에서부터 읽으며, 모두 읽으면 파일을 비워 디스크 사용량이 계속 늘지 않게 합니다.
+type spillQueue struct {
+	mu       sync.Mutex
+	f        *os.File
+	w        *bufio.Writer
+	writeOff int64 // 파일에 기록한 바이트 수 (버퍼 포함)
+	readOff  int64 // 읽어 간 바이트 수
+	n        atomic.Int64
+	temp     bool // 임시 파일이면 close 시 삭제
+}
+
+// openSpill : path 를 비우고 디스크 큐로 엽니다. path 가 비어 있으면 임시 파일을 만듭니다.
+func openSpill(path string) (*spillQueue, error) {
+	var (
+		f   *os.File
+		err error
+	)
+	if path == "" {
+		f, err = os.CreateTemp("", "eventgen-spill-*.ndjson")
+	} else {
+		f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open spill queue: %w", err)
+	}
+	return &spillQueue{f: f, w: bufio.NewWriterSize(f, 1<<20), temp: path == ""}, nil
+}
+
+// Len : 아직 읽지 않은 이벤트 수
+func (sq *spillQueue) Len() int64 {
+	return sq.n.Load()
+}
+
+func (sq *spillQueue) push(ev *event.Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	if _, err := sq.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	sq.writeOff += int64(len(data)) + 1
+	sq.n.Add(1)
+	return nil
+}
+
+// pop : 앞에서부터 최대 max 개의 이벤트를 읽습니다.
+func (sq *spillQueue) pop(max int) ([]*event.Event, error) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	if sq.n.Load() == 0 {
+		return nil, nil
+	}
+	if err := sq.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(io.NewSectionReader(sq.f, sq.readOff, sq.writeOff-sq.readOff))
+	out := make([]*event.Event, 0, min(int64(max), sq.n.Load()))
+	for len(out) < max {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return out, err
+		}
+		sq.readOff += int64(len(line))
+		sq.n.Add(-1)
+
+		ev := &event.Event{}
+		if err := json.Unmarshal(line, ev); err != nil {
+			return out, fmt.Errorf("decode spilled event: %w", err)
+		}
+		out = append(out, ev)
+	}
+
+	// 모두 읽었으면 파일을 비우고 처음부터 다시 씀
+	if sq.n.Load() == 0 {
+		if err := sq.f.Truncate(0); err != nil {
+			return out, err
+		}
+		if _, err := sq.f.Seek(0, io.SeekStart); err != nil {
+			return out, err
+		}
+		sq.readOff, sq.writeOff = 0, 0
+	}
+	return out, nil
+}
+
+func (sq *spillQueue) close() error {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	err := sq.f.Close()
+	if sq.temp {
+		os.Remove(sq.f.Name())
+	}
+	return err
+}
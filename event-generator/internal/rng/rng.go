@@ -0,0 +1,64 @@
+package rng
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+)
+
+// Factory 는 키(세션 ID 등)별로 독립된 *rand.Rand 스트림을 만들어 줍니다.
+//
+// seed 가 0 이면 매 호출마다 전역 소스에서 시드를 뽑으므로 실행마다 결과가 달라지고,
+// seed 가 0 이 아니면 같은 (seed, key) 에 대해 항상 같은 난수열을 돌려주므로
+// 고루틴 실행 순서와 무관하게 세션 단위로 재현 가능한 결과를 얻을 수 있습니다.
+type Factory struct {
+	seed uint64
+}
+
+func NewFactory(seed uint64) *Factory {
+	return &Factory{seed: seed}
+}
+
+// Seeded : 재현 가능 모드 여부
+func (f *Factory) Seeded() bool {
+	return f != nil && f.seed != 0
+}
+
+// Seed : 설정된 시드 (0 이면 비결정 모드)
+func (f *Factory) Seed() uint64 {
+	if f == nil {
+		return 0
+	}
+	return f.seed
+}
+
+// For : key 전용 난수 스트림 생성
+// 반환된 *rand.Rand 는 thread-safe 하지 않으므로 한 고루틴(한 세션)에서만 사용해야 합니다.
+func (f *Factory) For(key string) *rand.Rand {
+	return rand.New(f.Source(key))
+}
+
+// Source : For 가 사용하는 난수 소스
+// PCG 는 MarshalBinary / UnmarshalBinary 로 상태를 저장하고 복원할 수 있으므로
+// 저장했다가 이어서 실행해야 하는 스트림(세션 등)은 소스를 직접 들고 있습니다.
+func (f *Factory) Source(key string) *rand.PCG {
+	if !f.Seeded() {
+		return RandomSource()
+	}
+	return rand.NewPCG(f.seed, hashKey(key))
+}
+
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
+
+// Random : 전역 소스에서 시드를 뽑아 만든 비결정 난수 스트림
+func Random() *rand.Rand {
+	return rand.New(RandomSource())
+}
+
+// RandomSource : 전역 소스에서 시드를 뽑아 만든 비결정 난수 소스
+func RandomSource() *rand.PCG {
+	return rand.NewPCG(rand.Uint64(), rand.Uint64())
+}
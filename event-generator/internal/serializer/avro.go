@@ -0,0 +1,256 @@
+package serializer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Avro 스키마 네임스페이스 (Protobuf package 와 동일)
+const schemaNamespace = "event_generator"
+
+// Extra 값(any)을 담는 Avro union 의 분기 순서 (인코딩 인덱스와 일치해야 함)
+const (
+	avroAnyNull = iota
+	avroAnyBool
+	avroAnyLong
+	avroAnyDouble
+	avroAnyString
+	avroAnyJSON // 배열/맵 등 복합 값은 JSON 문자열로 담음
+)
+
+// =======================
+// Avro schema
+// =======================
+
+type avroRecord struct {
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	Fields    []avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name    string `json:"name"`
+	Type    any    `json:"type"`
+	Default any    `json:"default"`
+}
+
+type avroMap struct {
+	Type   string `json:"type"`
+	Values any    `json:"values"`
+}
+
+type avroArray struct {
+	Type  string `json:"type"`
+	Items any    `json:"items"`
+}
+
+// avroSchema : 스키마 트리를 Avro 스키마(JSON)로 변환합니다.
+// 모든 필드에 기본값을 넣어 필드 추가 시 BACKWARD 호환을 유지합니다.
+func avroSchema(root *node) ([]byte, error) {
+	defined := map[*node]bool{}
+	jsonValueDefined := false
+
+	var typeOf func(n *node, top bool) any
+	typeOf = func(n *node, top bool) any {
+		switch n.kind {
+		case kindString:
+			return "string"
+		case kindLong:
+			return "long"
+		case kindDouble:
+			return "double"
+		case kindBool:
+			return "boolean"
+		case kindNullable:
+			return []any{"null", typeOf(n.elem, false)}
+		case kindMap:
+			return avroMap{Type: "map", Values: typeOf(n.elem, false)}
+		case kindArray:
+			return avroArray{Type: "array", Items: typeOf(n.elem, false)}
+		case kindAny:
+			var jsonValue any = "JsonValue"
+			if !jsonValueDefined {
+				jsonValueDefined = true
+				jsonValue = avroRecord{
+					Type:   "record",
+					Name:   "JsonValue",
+					Fields: []avroField{{Name: "json", Type: "string", Default: ""}},
+				}
+			}
+			return []any{"null", "boolean", "long", "double", "string", jsonValue}
+		case kindRecord:
+			if defined[n] {
+				return n.name
+			}
+			defined[n] = true
+			rec := avroRecord{Type: "record", Name: n.name}
+			if top {
+				rec.Namespace = schemaNamespace
+			}
+			for _, f := range n.fields {
+				rec.Fields = append(rec.Fields, avroField{
+					Name:    f.name,
+					Type:    typeOf(f.node, false),
+					Default: avroDefault(f.node),
+				})
+			}
+			return rec
+		}
+		panic(fmt.Sprintf("avro: unknown kind %d", n.kind))
+	}
+
+	return json.MarshalIndent(typeOf(root, true), "", "  ")
+}
+
+func avroDefault(n *node) any {
+	switch n.kind {
+	case kindString:
+		return ""
+	case kindLong, kindDouble:
+		return 0
+	case kindBool:
+		return false
+	case kindMap:
+		return map[string]any{}
+	case kindArray:
+		return []any{}
+	case kindRecord:
+		out := make(map[string]any, len(n.fields))
+		for _, f := range n.fields {
+			out[f.name] = avroDefault(f.node)
+		}
+		return out
+	default: // nullable, any → null
+		return nil
+	}
+}
+
+// =======================
+// Avro binary encoding
+// =======================
+
+func appendAvroLong(buf []byte, v int64) []byte {
+	return binary.AppendVarint(buf, v) // zigzag varint
+}
+
+func appendAvroString(buf []byte, s string) []byte {
+	buf = appendAvroLong(buf, int64(len(s)))
+	return append(buf, s...)
+}
+
+func appendAvroDouble(buf []byte, f float64) []byte {
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(f))
+}
+
+func appendAvroBool(buf []byte, b bool) []byte {
+	if b {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+// encodeAvro : 스키마 트리를 따라 v 를 Avro binary 로 인코딩합니다.
+// map 은 키 정렬 순서로 기록하여 같은 입력에 대해 항상 같은 바이트를 만듭니다.
+func encodeAvro(buf []byte, n *node, v reflect.Value) ([]byte, error) {
+	switch n.kind {
+	case kindString:
+		return appendAvroString(buf, v.String()), nil
+	case kindLong:
+		return appendAvroLong(buf, intValue(v)), nil
+	case kindDouble:
+		return appendAvroDouble(buf, v.Float()), nil
+	case kindBool:
+		return appendAvroBool(buf, v.Bool()), nil
+	case kindRecord:
+		var err error
+		for _, f := range n.fields {
+			if buf, err = encodeAvro(buf, f.node, v.Field(f.index)); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.name, err)
+			}
+		}
+		return buf, nil
+	case kindNullable:
+		if v.IsNil() {
+			return appendAvroLong(buf, 0), nil
+		}
+		buf = appendAvroLong(buf, 1)
+		return encodeAvro(buf, n.elem, v.Elem())
+	case kindMap:
+		if v.Len() > 0 {
+			buf = appendAvroLong(buf, int64(v.Len()))
+			var err error
+			for _, k := range sortedKeys(v) {
+				buf = appendAvroString(buf, k.String())
+				if buf, err = encodeAvro(buf, n.elem, v.MapIndex(k)); err != nil {
+					return nil, fmt.Errorf("%s: %w", k.String(), err)
+				}
+			}
+		}
+		return appendAvroLong(buf, 0), nil
+	case kindArray:
+		if v.Len() > 0 {
+			buf = appendAvroLong(buf, int64(v.Len()))
+			var err error
+			for i := 0; i < v.Len(); i++ {
+				if buf, err = encodeAvro(buf, n.elem, v.Index(i)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return appendAvroLong(buf, 0), nil
+	case kindAny:
+		return encodeAvroAny(buf, v)
+	}
+	return nil, fmt.Errorf("avro: unknown kind %d", n.kind)
+}
+
+func encodeAvroAny(buf []byte, v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return appendAvroLong(buf, avroAnyNull), nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return appendAvroBool(appendAvroLong(buf, avroAnyBool), v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return appendAvroLong(appendAvroLong(buf, avroAnyLong), intValue(v)), nil
+	case reflect.Float32, reflect.Float64:
+		return appendAvroDouble(appendAvroLong(buf, avroAnyDouble), v.Float()), nil
+	case reflect.String:
+		return appendAvroString(appendAvroLong(buf, avroAnyString), v.String()), nil
+	default:
+		raw, err := json.Marshal(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return appendAvroString(appendAvroLong(buf, avroAnyJSON), string(raw)), nil
+	}
+}
+
+// =======================
+// helpers
+// =======================
+
+func intValue(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	default:
+		return v.Int()
+	}
+}
+
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
@@ -0,0 +1,251 @@
+package serializer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =======================
+// Avro schema resolution
+// =======================
+
+// Avro 타입 승격 규칙 (writer → reader)
+var avroPromotions = map[string][]string{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+type avroNames map[string]map[string]any
+
+// avroCanRead : reader 스키마로 writer 스키마 데이터를 읽을 수 있는지 검사합니다. (Avro schema resolution 규칙)
+func avroCanRead(reader, writer string) error {
+	var r, w any
+	if err := json.Unmarshal([]byte(reader), &r); err != nil {
+		return fmt.Errorf("reader schema: %w", err)
+	}
+	if err := json.Unmarshal([]byte(writer), &w); err != nil {
+		return fmt.Errorf("writer schema: %w", err)
+	}
+
+	rn, wn := avroNames{}, avroNames{}
+	collectAvroNames(r, rn)
+	collectAvroNames(w, wn)
+	return avroResolve(r, w, rn, wn, "$")
+}
+
+func collectAvroNames(t any, names avroNames) {
+	switch v := t.(type) {
+	case []any:
+		for _, b := range v {
+			collectAvroNames(b, names)
+		}
+	case map[string]any:
+		if name, ok := v["name"].(string); ok {
+			names[name] = v
+		}
+		if fields, ok := v["fields"].([]any); ok {
+			for _, f := range fields {
+				if fm, ok := f.(map[string]any); ok {
+					collectAvroNames(fm["type"], names)
+				}
+			}
+		}
+		collectAvroNames(v["values"], names)
+		collectAvroNames(v["items"], names)
+	}
+}
+
+// avroType : 이름 참조를 풀고 (타입 이름, 정의) 를 반환합니다.
+func avroType(t any, names avroNames) (string, any) {
+	switch v := t.(type) {
+	case string:
+		if def, ok := names[v]; ok {
+			return avroType(def, names)
+		}
+		return v, v
+	case []any:
+		return "union", v
+	case map[string]any:
+		typ, _ := v["type"].(string)
+		switch typ {
+		case "record", "enum", "fixed", "map", "array":
+			return typ, v
+		default:
+			return avroType(v["type"], names)
+		}
+	}
+	return "", t
+}
+
+func avroResolve(r, w any, rn, wn avroNames, path string) error {
+	rt, rdef := avroType(r, rn)
+	wt, wdef := avroType(w, wn)
+
+	// writer 가 union 이면 모든 분기를 reader 가 읽을 수 있어야 함
+	if wt == "union" {
+		for i, b := range wdef.([]any) {
+			if err := avroResolve(r, b, rn, wn, fmt.Sprintf("%s<%d>", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// reader 가 union 이면 한 분기라도 writer 를 읽을 수 있으면 됨
+	if rt == "union" {
+		for _, b := range rdef.([]any) {
+			if avroResolve(b, w, rn, wn, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: writer type %s is not in reader union", path, wt)
+	}
+
+	if rt != wt {
+		for _, p := range avroPromotions[wt] {
+			if p == rt {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: type changed from %s to %s", path, wt, rt)
+	}
+
+	switch rt {
+	case "record":
+		rm, wm := rdef.(map[string]any), wdef.(map[string]any)
+		if rm["name"] != wm["name"] {
+			return fmt.Errorf("%s: record name changed from %v to %v", path, wm["name"], rm["name"])
+		}
+		wfields := map[string]map[string]any{}
+		for _, f := range asSlice(wm["fields"]) {
+			fm := f.(map[string]any)
+			wfields[fm["name"].(string)] = fm
+		}
+		for _, f := range asSlice(rm["fields"]) {
+			fm := f.(map[string]any)
+			name := fm["name"].(string)
+			wf, ok := wfields[name]
+			if !ok {
+				if _, hasDefault := fm["default"]; !hasDefault {
+					return fmt.Errorf("%s.%s: field added without a default", path, name)
+				}
+				continue
+			}
+			if err := avroResolve(fm["type"], wf["type"], rn, wn, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "map":
+		return avroResolve(rdef.(map[string]any)["values"], wdef.(map[string]any)["values"], rn, wn, path+"{}")
+	case "array":
+		return avroResolve(rdef.(map[string]any)["items"], wdef.(map[string]any)["items"], rn, wn, path+"[]")
+	case "enum":
+		rsyms := map[any]bool{}
+		for _, s := range asSlice(rdef.(map[string]any)["symbols"]) {
+			rsyms[s] = true
+		}
+		_, hasDefault := rdef.(map[string]any)["default"]
+		for _, s := range asSlice(wdef.(map[string]any)["symbols"]) {
+			if !rsyms[s] && !hasDefault {
+				return fmt.Errorf("%s: enum symbol %v removed", path, s)
+			}
+		}
+	case "fixed":
+		if rdef.(map[string]any)["size"] != wdef.(map[string]any)["size"] {
+			return fmt.Errorf("%s: fixed size changed", path)
+		}
+	}
+	return nil
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+// =======================
+// Protobuf field compatibility
+// =======================
+
+type protoField struct {
+	name string
+	typ  string
+}
+
+var (
+	protoMessageRe = regexp.MustCompile(`^message\s+(\w+)\s*\{`)
+	protoOneofRe   = regexp.MustCompile(`^oneof\s+\w+\s*\{`)
+	protoFieldRe   = regexp.MustCompile(`^(repeated\s+)?(map<[^>]+>|[\w.]+)\s+(\w+)\s*=\s*(\d+)\s*;`)
+)
+
+// 같은 wire 표현을 공유하여 서로 읽을 수 있는 타입 묶음
+var protoWireGroups = map[string]string{
+	"int32": "varint", "int64": "varint", "uint32": "varint", "uint64": "varint", "bool": "varint",
+	"sint32": "zigzag", "sint64": "zigzag",
+	"string": "bytes", "bytes": "bytes",
+	"fixed32": "fixed32", "sfixed32": "fixed32",
+	"fixed64": "fixed64", "sfixed64": "fixed64",
+}
+
+// parseProto : .proto 텍스트에서 message 별 필드 번호 → (이름, 타입) 을 추출합니다.
+func parseProto(src string) map[string]map[int]protoField {
+	msgs := map[string]map[int]protoField{}
+	var stack []string // message 이름 (oneof 블록은 상위 message 이름을 다시 push)
+
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case line == "":
+		case protoMessageRe.MatchString(line):
+			name := protoMessageRe.FindStringSubmatch(line)[1]
+			msgs[name] = map[int]protoField{}
+			stack = append(stack, name)
+		case protoOneofRe.MatchString(line):
+			if len(stack) > 0 {
+				stack = append(stack, stack[len(stack)-1])
+			}
+		case line == "}":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case len(stack) > 0 && protoFieldRe.MatchString(line):
+			m := protoFieldRe.FindStringSubmatch(line)
+			num, _ := strconv.Atoi(m[4])
+			msgs[stack[len(stack)-1]][num] = protoField{name: m[3], typ: m[1] + m[2]}
+		}
+	}
+	return msgs
+}
+
+// protoCanRead : 같은 필드 번호가 호환되지 않는 타입으로 바뀌었는지 검사합니다.
+// proto3 에서 필드 추가/삭제는 양방향 모두 호환됩니다.
+func protoCanRead(reader, writer string) error {
+	rm, wm := parseProto(reader), parseProto(writer)
+	for msg, rfields := range rm {
+		wfields, ok := wm[msg]
+		if !ok {
+			continue
+		}
+		for num, rf := range rfields {
+			wf, ok := wfields[num]
+			if !ok || rf.typ == wf.typ {
+				continue
+			}
+			rg, rok := protoWireGroups[rf.typ]
+			wg, wok := protoWireGroups[wf.typ]
+			if rok && wok && rg == wg {
+				continue
+			}
+			return fmt.Errorf("%s field %d: type changed from %s to %s", msg, num, wf.typ, rf.typ)
+		}
+	}
+	return nil
+}
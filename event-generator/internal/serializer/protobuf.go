@@ -0,0 +1,270 @@
+package serializer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// ExtraValue(oneof) 필드 번호
+const (
+	pbAnyBool   = 1
+	pbAnyInt    = 2
+	pbAnyDouble = 3
+	pbAnyString = 4
+	pbAnyJSON   = 5
+)
+
+// =======================
+// .proto schema
+// =======================
+
+// protoSchema : 스키마 트리를 proto3 정의로 변환합니다.
+// 필드 번호는 구조체 필드 순서(1부터)이므로 새 필드는 항상 구조체 끝에 추가해야 합니다.
+func protoSchema(root *node) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n", schemaNamespace)
+
+	hasAny := false
+	var err error
+	walkRecords(root, func(rec *node) {
+		fmt.Fprintf(&b, "\nmessage %s {\n", rec.name)
+		for i, f := range rec.fields {
+			t, terr := protoType(f.node)
+			if terr != nil && err == nil {
+				err = fmt.Errorf("%s.%s: %w", rec.name, f.name, terr)
+			}
+			if containsAny(f.node) {
+				hasAny = true
+			}
+			fmt.Fprintf(&b, "  %s %s = %d;\n", t, f.name, i+1)
+		}
+		b.WriteString("}\n")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if hasAny {
+		fmt.Fprintf(&b, `
+// 타입이 정해지지 않은 값 (attributes.extra)
+message ExtraValue {
+  oneof kind {
+    bool bool_value = %d;
+    sint64 int_value = %d;
+    double double_value = %d;
+    string string_value = %d;
+    string json_value = %d; // 배열/맵 등 복합 값
+  }
+}
+`, pbAnyBool, pbAnyInt, pbAnyDouble, pbAnyString, pbAnyJSON)
+	}
+	return []byte(b.String()), nil
+}
+
+func protoType(n *node) (string, error) {
+	switch n.kind {
+	case kindString:
+		return "string", nil
+	case kindLong:
+		return "int64", nil
+	case kindDouble:
+		return "double", nil
+	case kindBool:
+		return "bool", nil
+	case kindRecord:
+		return n.name, nil
+	case kindNullable:
+		return n.elem.name, nil
+	case kindAny:
+		return "ExtraValue", nil
+	case kindMap:
+		if n.elem.kind == kindMap || n.elem.kind == kindArray {
+			return "", fmt.Errorf("protobuf map values cannot be maps or arrays")
+		}
+		t, err := protoType(n.elem)
+		return "map<string, " + t + ">", err
+	case kindArray:
+		if n.elem.kind == kindMap || n.elem.kind == kindArray {
+			return "", fmt.Errorf("protobuf repeated fields cannot hold maps or arrays")
+		}
+		t, err := protoType(n.elem)
+		return "repeated " + t, err
+	}
+	return "", fmt.Errorf("protobuf: unknown kind %d", n.kind)
+}
+
+func containsAny(n *node) bool {
+	for ; n != nil; n = n.elem {
+		if n.kind == kindAny {
+			return true
+		}
+	}
+	return false
+}
+
+// =======================
+// Protobuf binary encoding
+// =======================
+
+func appendTag(buf []byte, num int, wire int) []byte {
+	return binary.AppendUvarint(buf, uint64(num)<<3|uint64(wire))
+}
+
+func appendBytesField(buf []byte, num int, b []byte) []byte {
+	buf = appendTag(buf, num, wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+// encodeProtoMessage : record 를 메시지 본문(태그/값 목록)으로 인코딩합니다.
+func encodeProtoMessage(buf []byte, rec *node, v reflect.Value) ([]byte, error) {
+	var err error
+	for i, f := range rec.fields {
+		if buf, err = encodeProtoField(buf, i+1, f.node, v.Field(f.index), false); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	return buf, nil
+}
+
+// encodeProtoField : 필드 하나를 인코딩합니다.
+// proto3 규칙에 따라 기본값(0, "", false)은 생략하되, map 원소처럼 존재 자체가 의미 있으면 force 로 기록합니다.
+func encodeProtoField(buf []byte, num int, n *node, v reflect.Value, force bool) ([]byte, error) {
+	switch n.kind {
+	case kindString:
+		if s := v.String(); s != "" || force {
+			buf = appendBytesField(buf, num, []byte(s))
+		}
+		return buf, nil
+	case kindLong:
+		if i := intValue(v); i != 0 || force {
+			buf = binary.AppendUvarint(appendTag(buf, num, wireVarint), uint64(i))
+		}
+		return buf, nil
+	case kindDouble:
+		if f := v.Float(); f != 0 || force {
+			buf = binary.LittleEndian.AppendUint64(appendTag(buf, num, wireFixed64), math.Float64bits(f))
+		}
+		return buf, nil
+	case kindBool:
+		if b := v.Bool(); b || force {
+			buf = appendTag(buf, num, wireVarint)
+			if b {
+				buf = append(buf, 1)
+			} else {
+				buf = append(buf, 0)
+			}
+		}
+		return buf, nil
+	case kindRecord:
+		msg, err := encodeProtoMessage(nil, n, v)
+		if err != nil {
+			return nil, err
+		}
+		return appendBytesField(buf, num, msg), nil
+	case kindNullable:
+		if v.IsNil() {
+			return buf, nil
+		}
+		return encodeProtoField(buf, num, n.elem, v.Elem(), true)
+	case kindMap:
+		var err error
+		for _, k := range sortedKeys(v) {
+			entry := appendBytesField(nil, 1, []byte(k.String()))
+			if entry, err = encodeProtoField(entry, 2, n.elem, v.MapIndex(k), true); err != nil {
+				return nil, fmt.Errorf("%s: %w", k.String(), err)
+			}
+			buf = appendBytesField(buf, num, entry)
+		}
+		return buf, nil
+	case kindArray:
+		var err error
+		switch n.elem.kind {
+		case kindLong, kindDouble, kindBool:
+			// 스칼라 배열은 packed 인코딩
+			if v.Len() == 0 {
+				return buf, nil
+			}
+			var packed []byte
+			for i := 0; i < v.Len(); i++ {
+				packed = appendPackedScalar(packed, n.elem, v.Index(i))
+			}
+			return appendBytesField(buf, num, packed), nil
+		default:
+			for i := 0; i < v.Len(); i++ {
+				if buf, err = encodeProtoField(buf, num, n.elem, v.Index(i), true); err != nil {
+					return nil, err
+				}
+			}
+			return buf, nil
+		}
+	case kindAny:
+		msg, err := encodeProtoAny(v)
+		if err != nil {
+			return nil, err
+		}
+		return appendBytesField(buf, num, msg), nil
+	}
+	return nil, fmt.Errorf("protobuf: unknown kind %d", n.kind)
+}
+
+func appendPackedScalar(buf []byte, n *node, v reflect.Value) []byte {
+	switch n.kind {
+	case kindLong:
+		return binary.AppendUvarint(buf, uint64(intValue(v)))
+	case kindDouble:
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v.Float()))
+	default: // bool
+		if v.Bool() {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	}
+}
+
+// encodeProtoAny : ExtraValue 메시지 본문
+func encodeProtoAny(v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	var buf []byte
+	switch v.Kind() {
+	case reflect.Bool:
+		buf = appendTag(buf, pbAnyBool, wireVarint)
+		if v.Bool() {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		buf = binary.AppendVarint(appendTag(buf, pbAnyInt, wireVarint), intValue(v)) // sint64 (zigzag)
+	case reflect.Float32, reflect.Float64:
+		buf = binary.LittleEndian.AppendUint64(appendTag(buf, pbAnyDouble, wireFixed64), math.Float64bits(v.Float()))
+	case reflect.String:
+		buf = appendBytesField(buf, pbAnyString, []byte(v.String()))
+	default:
+		raw, err := json.Marshal(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		buf = appendBytesField(buf, pbAnyJSON, raw)
+	}
+	return buf, nil
+}
@@ -0,0 +1,291 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// 호환성 레벨 (Confluent Schema Registry 와 동일한 의미)
+const (
+	CompatNone               = "NONE"
+	CompatBackward           = "BACKWARD" // 새 스키마로 직전 버전 데이터를 읽을 수 있어야 함
+	CompatForward            = "FORWARD"  // 직전 버전 스키마로 새 데이터를 읽을 수 있어야 함
+	CompatFull               = "FULL"     // BACKWARD + FORWARD
+	CompatBackwardTransitive = "BACKWARD_TRANSITIVE"
+	CompatForwardTransitive  = "FORWARD_TRANSITIVE"
+	CompatFullTransitive     = "FULL_TRANSITIVE"
+)
+
+// 스키마 타입
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+)
+
+// ErrIncompatible : 호환성 규칙 위반
+var ErrIncompatible = errors.New("schema is incompatible")
+
+// RegisteredSchema : 레지스트리에 등록된 스키마 한 버전
+type RegisteredSchema struct {
+	ID         int    `json:"id"`
+	Version    int    `json:"version"`
+	SchemaType string `json:"schema_type"`
+	Schema     string `json:"schema"`
+}
+
+type subjectEntry struct {
+	Compatibility string             `json:"compatibility,omitempty"`
+	Versions      []RegisteredSchema `json:"versions"`
+}
+
+type registryFile struct {
+	NextID        int                      `json:"next_id"`
+	Compatibility string                   `json:"compatibility"`
+	Subjects      map[string]*subjectEntry `json:"subjects"`
+}
+
+// =======================
+// FileRegistry
+// =======================
+
+// FileRegistry 는 Schema Registry 를 흉내 내는 로컬 JSON 파일 기반 저장소입니다.
+// subject 별 버전 관리, 전역 스키마 ID, 호환성 검사를 실제 레지스트리 없이 테스트할 수 있습니다.
+type FileRegistry struct {
+	path string
+	mu   sync.Mutex
+	data registryFile
+}
+
+// OpenRegistry : path 의 레지스트리 파일을 열고, 없으면 새로 만듭니다. (기본 호환성 BACKWARD)
+func OpenRegistry(path string) (*FileRegistry, error) {
+	r := &FileRegistry{
+		path: path,
+		data: registryFile{
+			NextID:        1,
+			Compatibility: CompatBackward,
+			Subjects:      map[string]*subjectEntry{},
+		},
+	}
+
+	raw, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return r, r.save()
+	case err != nil:
+		return nil, fmt.Errorf("read schema registry %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(raw, &r.data); err != nil {
+		return nil, fmt.Errorf("parse schema registry %s: %w", path, err)
+	}
+	if r.data.Subjects == nil {
+		r.data.Subjects = map[string]*subjectEntry{}
+	}
+	if r.data.NextID < 1 {
+		r.data.NextID = 1
+	}
+	return r, nil
+}
+
+// SetCompatibility : subject 의 호환성 레벨을 지정합니다. (subject 가 비어 있으면 전역 기본값)
+func (r *FileRegistry) SetCompatibility(subject, level string) error {
+	switch level {
+	case CompatNone, CompatBackward, CompatForward, CompatFull,
+		CompatBackwardTransitive, CompatForwardTransitive, CompatFullTransitive:
+	default:
+		return fmt.Errorf("unknown compatibility level %q", level)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if subject == "" {
+		r.data.Compatibility = level
+	} else {
+		r.subject(subject).Compatibility = level
+	}
+	return r.save()
+}
+
+// Register : 스키마를 subject 에 등록하고 등록 정보를 반환합니다.
+// 이미 같은 스키마가 등록되어 있으면 기존 ID/버전을 그대로 돌려주고,
+// 새 스키마면 호환성 레벨에 따라 기존 버전과 비교한 뒤 새 버전으로 추가합니다.
+func (r *FileRegistry) Register(subject, schemaType string, schema []byte) (RegisteredSchema, error) {
+	canonical, err := canonicalSchema(schemaType, schema)
+	if err != nil {
+		return RegisteredSchema{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.subject(subject)
+	for _, v := range entry.Versions {
+		if v.SchemaType == schemaType && v.Schema == canonical {
+			return v, nil
+		}
+	}
+
+	level := entry.Compatibility
+	if level == "" {
+		level = r.data.Compatibility
+	}
+	if err := checkCompatibility(level, schemaType, canonical, entry.Versions); err != nil {
+		return RegisteredSchema{}, fmt.Errorf("register %s: %w", subject, err)
+	}
+
+	// 다른 subject 에 같은 스키마가 있으면 ID 를 재사용
+	id := 0
+	for _, other := range r.data.Subjects {
+		for _, v := range other.Versions {
+			if v.SchemaType == schemaType && v.Schema == canonical {
+				id = v.ID
+			}
+		}
+	}
+	if id == 0 {
+		id = r.data.NextID
+		r.data.NextID++
+	}
+
+	rs := RegisteredSchema{
+		ID:         id,
+		Version:    len(entry.Versions) + 1,
+		SchemaType: schemaType,
+		Schema:     canonical,
+	}
+	entry.Versions = append(entry.Versions, rs)
+	return rs, r.save()
+}
+
+// SchemaByID : 스키마 ID 로 조회 (컨슈머 측 역직렬화용)
+func (r *FileRegistry) SchemaByID(id int) (RegisteredSchema, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, entry := range r.data.Subjects {
+		for _, v := range entry.Versions {
+			if v.ID == id {
+				return v, true
+			}
+		}
+	}
+	return RegisteredSchema{}, false
+}
+
+// Latest : subject 의 최신 버전
+func (r *FileRegistry) Latest(subject string) (RegisteredSchema, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.data.Subjects[subject]
+	if !ok || len(entry.Versions) == 0 {
+		return RegisteredSchema{}, false
+	}
+	return entry.Versions[len(entry.Versions)-1], true
+}
+
+func (r *FileRegistry) subject(name string) *subjectEntry {
+	entry, ok := r.data.Subjects[name]
+	if !ok {
+		entry = &subjectEntry{}
+		r.data.Subjects[name] = entry
+	}
+	return entry
+}
+
+// save : 임시 파일에 쓴 뒤 rename 하여 중간에 끊겨도 파일이 깨지지 않도록 합니다.
+func (r *FileRegistry) save() error {
+	raw, err := json.MarshalIndent(r.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(r.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.path)
+}
+
+// canonicalSchema : 공백 차이로 다른 스키마로 인식되지 않도록 정규화
+func canonicalSchema(schemaType string, schema []byte) (string, error) {
+	switch schemaType {
+	case SchemaTypeAvro:
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, schema); err != nil {
+			return "", fmt.Errorf("invalid avro schema: %w", err)
+		}
+		return buf.String(), nil
+	case SchemaTypeProtobuf:
+		return string(bytes.TrimSpace(schema)) + "\n", nil
+	default:
+		return "", fmt.Errorf("unknown schema type %q", schemaType)
+	}
+}
+
+// =======================
+// Compatibility
+// =======================
+
+func checkCompatibility(level, schemaType, schema string, versions []RegisteredSchema) error {
+	if level == CompatNone || len(versions) == 0 {
+		return nil
+	}
+
+	// transitive 가 아니면 최신 버전하고만 비교
+	targets := versions[len(versions)-1:]
+	backward, forward := false, false
+	switch level {
+	case CompatBackward:
+		backward = true
+	case CompatForward:
+		forward = true
+	case CompatFull:
+		backward, forward = true, true
+	case CompatBackwardTransitive:
+		backward, targets = true, versions
+	case CompatForwardTransitive:
+		forward, targets = true, versions
+	case CompatFullTransitive:
+		backward, forward, targets = true, true, versions
+	}
+
+	for _, old := range targets {
+		if old.SchemaType != schemaType {
+			return fmt.Errorf("%w: schema type changed from %s to %s", ErrIncompatible, old.SchemaType, schemaType)
+		}
+		if backward {
+			// 새 스키마(reader)로 이전 데이터(writer)를 읽을 수 있어야 함
+			if err := canRead(schemaType, schema, old.Schema); err != nil {
+				return fmt.Errorf("%w (BACKWARD vs version %d): %v", ErrIncompatible, old.Version, err)
+			}
+		}
+		if forward {
+			// 이전 스키마(reader)로 새 데이터(writer)를 읽을 수 있어야 함
+			if err := canRead(schemaType, old.Schema, schema); err != nil {
+				return fmt.Errorf("%w (FORWARD vs version %d): %v", ErrIncompatible, old.Version, err)
+			}
+		}
+	}
+	return nil
+}
+
+func canRead(schemaType, reader, writer string) error {
+	switch schemaType {
+	case SchemaTypeAvro:
+		return avroCanRead(reader, writer)
+	case SchemaTypeProtobuf:
+		return protoCanRead(reader, writer)
+	}
+	return fmt.Errorf("unknown schema type %q", schemaType)
+}
@@ -0,0 +1,144 @@
+package serializer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// =======================
+// Schema tree
+// =======================
+//
+// event.Event 구조체를 reflection 으로 한 번 훑어 만든 타입 트리입니다.
+// Avro/Protobuf 스키마 생성과 인코딩이 모두 이 트리를 기준으로 동작하므로
+// Go 구조체, 스키마 파일, 실제 바이트가 서로 어긋나지 않습니다.
+
+type kind int
+
+const (
+	kindString kind = iota
+	kindLong
+	kindDouble
+	kindBool
+	kindRecord
+	kindNullable // *struct
+	kindMap      // map[string]T
+	kindArray    // []T
+	kindAny      // interface{} (Extra 값)
+)
+
+type node struct {
+	kind   kind
+	name   string  // record 이름 (Go 타입 이름)
+	fields []field // record
+	elem   *node   // nullable / map / array 의 원소 타입
+}
+
+type field struct {
+	name  string // json 태그 이름
+	index int    // 구조체 필드 인덱스
+	node  *node
+}
+
+// buildSchema : Go 타입을 스키마 트리로 변환합니다.
+func buildSchema(t reflect.Type) (*node, error) {
+	return buildNode(t, map[reflect.Type]*node{})
+}
+
+func buildNode(t reflect.Type, records map[reflect.Type]*node) (*node, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return &node{kind: kindString}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &node{kind: kindLong}, nil
+	case reflect.Float32, reflect.Float64:
+		return &node{kind: kindDouble}, nil
+	case reflect.Bool:
+		return &node{kind: kindBool}, nil
+	case reflect.Interface:
+		return &node{kind: kindAny}, nil
+	case reflect.Pointer:
+		if t.Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("unsupported pointer type %s", t)
+		}
+		elem, err := buildNode(t.Elem(), records)
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: kindNullable, elem: elem}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t)
+		}
+		elem, err := buildNode(t.Elem(), records)
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: kindMap, elem: elem}, nil
+	case reflect.Slice:
+		elem, err := buildNode(t.Elem(), records)
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: kindArray, elem: elem}, nil
+	case reflect.Struct:
+		if n, ok := records[t]; ok {
+			return n, nil
+		}
+		n := &node{kind: kindRecord, name: t.Name()}
+		records[t] = n
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name := jsonName(sf)
+			if name == "-" {
+				continue
+			}
+			fn, err := buildNode(sf.Type, records)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", t.Name(), sf.Name, err)
+			}
+			n.fields = append(n.fields, field{name: name, index: i, node: fn})
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func jsonName(sf reflect.StructField) string {
+	tag := sf.Tag.Get("json")
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+// walkRecords : 트리에 등장하는 record 를 처음 등장한 순서대로 방문합니다.
+func walkRecords(root *node, fn func(*node)) {
+	seen := map[*node]bool{}
+	var walk func(*node)
+	walk = func(n *node) {
+		if n == nil {
+			return
+		}
+		if n.kind == kindRecord {
+			if seen[n] {
+				return
+			}
+			seen[n] = true
+			fn(n)
+			for _, f := range n.fields {
+				walk(f.node)
+			}
+			return
+		}
+		walk(n.elem)
+	}
+	walk(root)
+}
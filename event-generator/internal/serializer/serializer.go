@@ -0,0 +1,176 @@
+package serializer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"event-generator/internal/event"
+)
+
+// 지원하는 직렬화 포맷
+const (
+	FormatJSON     = "json"
+	FormatAvro     = "avro"
+	FormatProtobuf = "protobuf"
+)
+
+// Confluent wire format : magic byte(0) + 4바이트 big-endian 스키마 ID + 본문
+const (
+	wireMagic      = 0
+	wireHeaderSize = 5
+)
+
+// =======================
+// Serializer Interface
+// =======================
+
+// Serializer 는 Kafka 메시지 Value 를 만드는 이벤트 인코더입니다.
+// 여러 Worker 고루틴에서 동시에 호출되므로 thread-safe 해야 합니다.
+type Serializer interface {
+	// Format : 포맷 이름 (json / avro / protobuf)
+	Format() string
+	// Serialize : 이벤트 하나를 메시지 Value 로 인코딩합니다.
+	Serialize(ev *event.Event) ([]byte, error)
+}
+
+var (
+	eventSchemaOnce sync.Once
+	eventSchema     *node
+	eventSchemaErr  error
+)
+
+// schema : event.Event 의 스키마 트리 (한 번만 생성)
+func schema() (*node, error) {
+	eventSchemaOnce.Do(func() {
+		eventSchema, eventSchemaErr = buildSchema(reflect.TypeOf(event.Event{}))
+	})
+	return eventSchema, eventSchemaErr
+}
+
+// AvroSchema : event.Event 에서 생성한 Avro 스키마 (schemas/event.avsc)
+func AvroSchema() ([]byte, error) {
+	root, err := schema()
+	if err != nil {
+		return nil, err
+	}
+	return avroSchema(root)
+}
+
+// ProtoSchema : event.Event 에서 생성한 proto3 정의 (schemas/event.proto)
+func ProtoSchema() ([]byte, error) {
+	root, err := schema()
+	if err != nil {
+		return nil, err
+	}
+	return protoSchema(root)
+}
+
+// New : 포맷 이름으로 Serializer 를 생성합니다.
+// avro/protobuf 는 reg 에 subject 로 스키마를 등록(호환성 검사 포함)하고,
+// 받은 스키마 ID 를 Confluent wire format 헤더로 붙입니다. reg 가 nil 이면 헤더 없이 본문만 씁니다.
+func New(format string, reg *FileRegistry, subject string) (Serializer, error) {
+	switch format {
+	case "", FormatJSON:
+		return JSONSerializer{}, nil
+	case FormatAvro, FormatProtobuf:
+	default:
+		return nil, fmt.Errorf("unknown serializer format: %q", format)
+	}
+
+	root, err := schema()
+	if err != nil {
+		return nil, fmt.Errorf("build event schema: %w", err)
+	}
+
+	var (
+		schemaType string
+		def        []byte
+	)
+	if format == FormatAvro {
+		schemaType = SchemaTypeAvro
+		def, err = avroSchema(root)
+	} else {
+		schemaType = SchemaTypeProtobuf
+		def, err = protoSchema(root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generate %s schema: %w", format, err)
+	}
+
+	var header []byte
+	if reg != nil {
+		rs, err := reg.Register(subject, schemaType, def)
+		if err != nil {
+			return nil, err
+		}
+		header = wireHeader(rs.ID)
+		if format == FormatProtobuf {
+			// 최상위 message(Event) 를 가리키는 message index 목록 [0] 의 축약형
+			header = append(header, 0)
+		}
+	}
+
+	if format == FormatAvro {
+		return &AvroSerializer{root: root, header: header}, nil
+	}
+	return &ProtobufSerializer{root: root, header: header}, nil
+}
+
+func wireHeader(id int) []byte {
+	h := make([]byte, wireHeaderSize)
+	h[0] = wireMagic
+	binary.BigEndian.PutUint32(h[1:], uint32(id))
+	return h
+}
+
+// SchemaID : Confluent wire format 메시지에서 스키마 ID 를 읽습니다. (컨슈머/검증용)
+func SchemaID(msg []byte) (int, error) {
+	if len(msg) < wireHeaderSize || msg[0] != wireMagic {
+		return 0, fmt.Errorf("not a schema registry framed message")
+	}
+	return int(binary.BigEndian.Uint32(msg[1:wireHeaderSize])), nil
+}
+
+// =======================
+// Implementations
+// =======================
+
+// JSONSerializer : 기존과 동일한 JSON 인코딩 (스키마 ID 없음)
+type JSONSerializer struct{}
+
+func (JSONSerializer) Format() string { return FormatJSON }
+
+func (JSONSerializer) Serialize(ev *event.Event) ([]byte, error) {
+	return json.Marshal(ev)
+}
+
+// AvroSerializer : Avro binary 인코딩
+type AvroSerializer struct {
+	root   *node
+	header []byte
+}
+
+func (s *AvroSerializer) Format() string { return FormatAvro }
+
+func (s *AvroSerializer) Serialize(ev *event.Event) ([]byte, error) {
+	buf := make([]byte, 0, 256)
+	buf = append(buf, s.header...)
+	return encodeAvro(buf, s.root, reflect.ValueOf(ev).Elem())
+}
+
+// ProtobufSerializer : proto3 binary 인코딩
+type ProtobufSerializer struct {
+	root   *node
+	header []byte
+}
+
+func (s *ProtobufSerializer) Format() string { return FormatProtobuf }
+
+func (s *ProtobufSerializer) Serialize(ev *event.Event) ([]byte, error) {
+	buf := make([]byte, 0, 256)
+	buf = append(buf, s.header...)
+	return encodeProtoMessage(buf, s.root, reflect.ValueOf(ev).Elem())
+}
@@ -0,0 +1,121 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"event-generator/internal/event"
+)
+
+// ErrInjected : 장애 주입으로 실패시킨 Write
+var ErrInjected = errors.New("injected sink failure")
+
+// FaultConfig : Sink 장애 주입 설정 (확률은 0~1)
+type FaultConfig struct {
+	Enabled       bool    `json:"enabled" yaml:"enabled"`
+	ErrorRate     float64 `json:"error_rate" yaml:"error_rate"`         // 배치 Write 를 실패시킬 확률
+	DropRate      float64 `json:"drop_rate" yaml:"drop_rate"`           // 이벤트를 조용히 버릴 확률 (성공으로 기록됨)
+	DuplicateRate float64 `json:"duplicate_rate" yaml:"duplicate_rate"` // 이벤트를 한 번 더 보낼 확률
+	LatencyMs     int     `json:"latency_ms" yaml:"latency_ms"`         // Write 마다 추가할 지연
+}
+
+// Validate : 확률 / 지연 값 검사
+func (c FaultConfig) Validate() error {
+	var errs []error
+	for name, v := range map[string]float64{"error_rate": c.ErrorRate, "drop_rate": c.DropRate, "duplicate_rate": c.DuplicateRate} {
+		if v < 0 || v > 1 {
+			errs = append(errs, fmt.Errorf("%s must be in [0, 1] (got %g)", name, v))
+		}
+	}
+	if c.LatencyMs < 0 {
+		errs = append(errs, fmt.Errorf("latency_ms must be >= 0 (got %d)", c.LatencyMs))
+	}
+	return errors.Join(errs...)
+}
+
+// =======================
+// FaultSink
+// =======================
+
+// FaultSink 는 다른 Sink 를 감싸서 실패 / 유실 / 중복 / 지연을 주입합니다.
+// 설정은 실행 중에 SetConfig 로 바꿀 수 있으며, 비활성화 상태에서는 그대로 전달만 합니다.
+// 장애는 전역 rand 로 뽑으므로 seed 모드에서도 재현되지 않습니다.
+type FaultSink struct {
+	next Sink
+	cfg  atomic.Pointer[FaultConfig]
+}
+
+func NewFaultSink(next Sink, cfg FaultConfig) *FaultSink {
+	s := &FaultSink{next: next}
+	s.cfg.Store(&cfg)
+	return s
+}
+
+// Config : 현재 장애 주입 설정
+func (s *FaultSink) Config() FaultConfig {
+	return *s.cfg.Load()
+}
+
+// SetConfig : 장애 주입 설정을 즉시 교체합니다.
+func (s *FaultSink) SetConfig(cfg FaultConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	s.cfg.Store(&cfg)
+	return nil
+}
+
+func (s *FaultSink) Write(ctx context.Context, events []*event.Event) error {
+	cfg := s.cfg.Load()
+	if !cfg.Enabled {
+		return s.next.Write(ctx, events)
+	}
+
+	if cfg.LatencyMs > 0 {
+		select {
+		case <-time.After(time.Duration(cfg.LatencyMs) * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if rand.Float64() < cfg.ErrorRate {
+		return ErrInjected
+	}
+
+	if cfg.DropRate > 0 || cfg.DuplicateRate > 0 {
+		out := make([]*event.Event, 0, len(events))
+		for _, e := range events {
+			if rand.Float64() < cfg.DropRate {
+				continue
+			}
+			out = append(out, e)
+			if rand.Float64() < cfg.DuplicateRate {
+				out = append(out, e)
+			}
+		}
+		events = out
+	}
+	return s.next.Write(ctx, events)
+}
+
+func (s *FaultSink) Flush(ctx context.Context) error {
+	return s.next.Flush(ctx)
+}
+
+func (s *FaultSink) Close() error {
+	return s.next.Close()
+}
+
+func (s *FaultSink) Name() string {
+	return s.next.Name()
+}
+
+// ReportsDelivery : 감싼 Sink 의 전송 확정 기록 방식을 그대로 따릅니다.
+func (s *FaultSink) ReportsDelivery() bool {
+	r, ok := s.next.(DeliveryReporter)
+	return ok && r.ReportsDelivery()
+}
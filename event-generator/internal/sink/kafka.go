@@ -0,0 +1,147 @@
+package sink
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"event-generator/internal/event"
+	"event-generator/internal/metrics"
+	"event-generator/internal/serializer"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// writer 통계(재시도 횟수)를 메트릭으로 옮기는 주기
+const statsInterval = time.Second
+
+// KafkaSink : 이벤트를 Serializer(JSON / Avro / Protobuf)로 직렬화하여 Kafka 토픽에 비동기 전송합니다.
+// UserID 를 메시지 Key 로 사용하여 유저 단위 순서를 보장합니다.
+//
+// Async 모드에서는 WriteMessages 가 내부 큐 적재만 하고 바로 반환하므로,
+// 실제 전송 결과는 Completion 콜백에서 파티션 단위로 메트릭에 기록합니다.
+type KafkaSink struct {
+	topic   string
+	writer  *kafka.Writer
+	ser     serializer.Serializer
+	metrics metrics.Metrics
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewKafkaSink : ser 가 nil 이면 JSON 으로 직렬화하고, m 이 nil 이면 전송 확정 지표를 기록하지 않습니다.
+func NewKafkaSink(brokers []string, topic string, ser serializer.Serializer, m metrics.Metrics) *KafkaSink {
+	if ser == nil {
+		ser = serializer.JSONSerializer{}
+	}
+	s := &KafkaSink{
+		topic:   topic,
+		ser:     ser,
+		metrics: m,
+		stop:    make(chan struct{}),
+	}
+	s.writer = &kafka.Writer{
+		Addr:     kafka.TCP(brokers...),
+		Topic:    topic,
+		Balancer: &kafka.Hash{},
+		// 수동 배칭 대신 라이브러리 설정을 활용
+		BatchSize:    1000,
+		BatchTimeout: 50 * time.Millisecond,
+		RequiredAcks: kafka.RequireOne,
+		Async:        true, // 비동기 모드이므로 WriteMessages는 논블로킹
+		Compression:  kafka.Snappy,
+	}
+
+	if m != nil {
+		s.writer.Completion = s.onCompletion
+		s.wg.Add(1)
+		go s.collectStats()
+	}
+	return s
+}
+
+func (s *KafkaSink) Name() string {
+	return s.topic
+}
+
+// ReportsDelivery : 전송 결과는 Completion 콜백에서 기록합니다.
+func (s *KafkaSink) ReportsDelivery() bool {
+	return s.metrics != nil
+}
+
+func (s *KafkaSink) Write(ctx context.Context, events []*event.Event) error {
+	// 적재 시각을 메시지 Time 에 기록해 두고 Completion 에서 전송 지연을 계산합니다.
+	now := time.Now()
+	msgs := make([]kafka.Message, 0, len(events))
+	for _, ev := range events {
+		msgBytes, err := s.ser.Serialize(ev)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, kafka.Message{
+			Key:   []byte(ev.UserID),
+			Value: msgBytes,
+			Time:  now,
+		})
+	}
+	return s.writer.WriteMessages(ctx, msgs...)
+}
+
+// onCompletion : 한 파티션으로 보낸 배치의 최종 결과 (재시도 포함)
+func (s *KafkaSink) onCompletion(messages []kafka.Message, err error) {
+	if len(messages) == 0 {
+		return
+	}
+
+	// 응답을 받지 못한 실패 배치는 토픽/파티션이 채워지지 않습니다.
+	topic, partition := messages[0].Topic, messages[0].Partition
+	if topic == "" {
+		topic, partition = s.topic, -1
+	}
+
+	if err != nil {
+		s.metrics.IncDeliveryFailed(topic, partition, len(messages))
+		s.metrics.IncError("kafka_delivery")
+		return
+	}
+
+	s.metrics.IncDelivered(topic, partition, len(messages))
+	now := time.Now()
+	for i := range messages {
+		s.metrics.ObserveProduceLatency(now.Sub(messages[i].Time))
+	}
+}
+
+// collectStats : kafka.Writer.Stats() 는 호출 간 증가분을 돌려주므로 주기적으로 누적합니다.
+func (s *KafkaSink) collectStats() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			s.metrics.IncRetries(s.topic, s.writer.Stats().Retries)
+			return
+		case <-ticker.C:
+			s.metrics.IncRetries(s.topic, s.writer.Stats().Retries)
+		}
+	}
+}
+
+// Flush : Async 모드에서는 kafka.Writer 가 BatchTimeout 마다 자체적으로 전송하므로 별도 처리가 없습니다.
+// 남은 배치는 Close 시점에 모두 전송됩니다.
+func (s *KafkaSink) Flush(ctx context.Context) error {
+	return nil
+}
+
+// Close : 남은 배치를 전송하고 (Completion 호출 완료까지 대기) 통계 수집을 멈춥니다.
+func (s *KafkaSink) Close() error {
+	err := s.writer.Close()
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.wg.Wait()
+	return err
+}
@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"context"
+	"sync"
+
+	"event-generator/internal/event"
+)
+
+// MemorySink : 이벤트를 메모리에 보관하는 Sink (테스트/검증용)
+type MemorySink struct {
+	mu     sync.Mutex
+	events []*event.Event
+	closed bool
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Write(ctx context.Context, events []*event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *MemorySink) Name() string {
+	return "memory"
+}
+
+func (s *MemorySink) Flush(ctx context.Context) error {
+	return nil
+}
+
+func (s *MemorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// Events : 지금까지 기록된 이벤트의 복사본
+func (s *MemorySink) Events() []*event.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*event.Event(nil), s.events...)
+}
+
+// Len : 기록된 이벤트 수
+func (s *MemorySink) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+// Closed : Close 호출 여부
+func (s *MemorySink) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// Reset : 기록된 이벤트를 비웁니다.
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = nil
+}
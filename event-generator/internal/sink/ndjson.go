@@ -0,0 +1,93 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"event-generator/internal/event"
+)
+
+// NDJSONSink : 이벤트를 한 줄에 하나씩 JSON(newline-delimited JSON)으로 기록합니다.
+// 파일/표준출력 Sink 의 공통 구현입니다.
+type NDJSONSink struct {
+	name   string
+	mu     sync.Mutex
+	w      *bufio.Writer
+	enc    *json.Encoder
+	closer io.Closer // nil 이면 Close 시 하위 writer 를 닫지 않음 (stdout)
+}
+
+// NewWriterSink : 임의의 io.Writer 로 NDJSON 을 기록하는 Sink
+// w 는 Close 시 닫히지 않습니다.
+func NewWriterSink(w io.Writer) *NDJSONSink {
+	bw := bufio.NewWriterSize(w, 1<<20)
+	return &NDJSONSink{
+		name: "writer",
+		w:    bw,
+		enc:  json.NewEncoder(bw),
+	}
+}
+
+// NewFileSink : path 파일을 새로 만들어 NDJSON 으로 기록합니다. (오프라인 데이터셋 생성용)
+func NewFileSink(path string) (*NDJSONSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file sink: path is empty")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("file sink: %w", err)
+	}
+	s := NewWriterSink(f)
+	s.name = TypeFile
+	s.closer = f
+	return s, nil
+}
+
+// NewStdoutSink : 표준출력 w 로 NDJSON 을 기록합니다. (w 는 Close 시 닫히지 않음)
+// 진단 로그와 섞이지 않도록 표준출력은 이 Sink 만 사용해야 합니다.
+func NewStdoutSink(w io.Writer) *NDJSONSink {
+	s := NewWriterSink(w)
+	s.name = TypeStdout
+	return s
+}
+
+func (s *NDJSONSink) Name() string {
+	return s.name
+}
+
+func (s *NDJSONSink) Write(ctx context.Context, events []*event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ev := range events {
+		// json.Encoder 는 각 값 뒤에 개행을 붙입니다.
+		if err := s.enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NDJSONSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Flush()
+}
+
+func (s *NDJSONSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.w.Flush()
+	if s.closer != nil {
+		if cerr := s.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
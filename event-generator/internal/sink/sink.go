@@ -0,0 +1,80 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"event-generator/internal/event"
+	"event-generator/internal/metrics"
+	"event-generator/internal/serializer"
+)
+
+// =======================
+// Sink Interface
+// =======================
+
+// Sink 는 Worker 가 생성된 이벤트를 내보내는 출력 대상입니다.
+// 구현체는 여러 Worker 고루틴에서 동시에 호출될 수 있으므로 thread-safe 해야 합니다.
+type Sink interface {
+	// Write : 이벤트 묶음을 전송(또는 버퍼링)합니다.
+	Write(ctx context.Context, events []*event.Event) error
+	// Flush : 버퍼링된 이벤트를 모두 내보냅니다.
+	Flush(ctx context.Context) error
+	// Close : Flush 후 자원을 해제합니다.
+	Close() error
+	// Name : 메트릭 라벨로 사용할 출력 대상 이름
+	Name() string
+}
+
+// DeliveryReporter 는 전송 확정(ack)을 비동기로 직접 메트릭에 기록하는 Sink 가 구현합니다.
+// 구현하지 않은 Sink 는 Write 가 성공한 시점을 전송 완료로 간주합니다. (Worker 가 기록)
+type DeliveryReporter interface {
+	ReportsDelivery() bool
+}
+
+// 지원하는 Sink 타입
+const (
+	TypeKafka  = "kafka"
+	TypeFile   = "file"
+	TypeStdout = "stdout"
+)
+
+// Options : New 에서 사용하는 Sink 생성 옵션
+type Options struct {
+	Type string
+
+	// kafka
+	Brokers []string
+	Topic   string
+	// 메시지 인코딩 (nil 이면 JSON)
+	Serializer serializer.Serializer
+
+	// file
+	Path string
+
+	// stdout (nil 이면 os.Stdout)
+	Stdout io.Writer
+
+	// 전송 확정 지표 기록용 (kafka)
+	Metrics metrics.Metrics
+}
+
+// New : 타입 이름으로 Sink 를 생성합니다. (cmd/generator 에서 사용)
+func New(opts Options) (Sink, error) {
+	switch opts.Type {
+	case TypeKafka:
+		return NewKafkaSink(opts.Brokers, opts.Topic, opts.Serializer, opts.Metrics), nil
+	case TypeFile:
+		return NewFileSink(opts.Path)
+	case TypeStdout:
+		w := opts.Stdout
+		if w == nil {
+			w = os.Stdout
+		}
+		return NewStdoutSink(w), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type: %q", opts.Type)
+	}
+}
@@ -0,0 +1,156 @@
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// =======================
+// BoltStore
+// =======================
+
+// bbolt 버킷 이름
+var sessionsBucket = []byte("sessions")
+
+// BoltStore : bbolt 파일 하나에 세션을 JSON 으로 저장하는 SessionStore
+// 프로세스를 내렸다가 다시 띄워도 세션이 남으므로 긴 시뮬레이션을 멈췄다가 이어서 실행할 때 사용합니다.
+// Put / Delete 는 호출마다 트랜잭션 하나로 기록하므로 여러 세션은 한 번에 넘기는 편이 빠릅니다.
+type BoltStore struct {
+	db   *bolt.DB
+	path string
+}
+
+// OpenBoltStore : path 의 bbolt 파일을 엽니다. (없으면 생성)
+// 다른 프로세스가 같은 파일을 열고 있으면 기다리지 않고 오류를 반환합니다.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open session store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(sessionsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open session store %s: %w", path, err)
+	}
+	return &BoltStore{db: db, path: path}, nil
+}
+
+// Path : 저장 파일 경로
+func (b *BoltStore) Path() string {
+	return b.path
+}
+
+func (b *BoltStore) Get(sessionID string) (*Session, bool, error) {
+	var s *Session
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(sessionsBucket).Get([]byte(sessionID))
+		if data == nil {
+			return nil
+		}
+		var err error
+		s, err = decodeSession(data)
+		return err
+	})
+	return s, s != nil, err
+}
+
+func (b *BoltStore) Put(sessions ...*Session) error {
+	type entry struct {
+		key, data []byte
+	}
+	// 인코딩은 트랜잭션 밖에서 (쓰기 락을 짧게 잡음)
+	entries := make([]entry, 0, len(sessions))
+	for _, s := range sessions {
+		r, err := newSessionRecord(s)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("session %s: %w", s.ID, err)
+		}
+		entries = append(entries, entry{[]byte(s.ID), data})
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sessionsBucket)
+		for _, e := range entries {
+			if err := bucket.Put(e.key, e.data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *BoltStore) Delete(sessionIDs ...string) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sessionsBucket)
+		for _, id := range sessionIDs {
+			if err := bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *BoltStore) Expire(now int64, fn func(*Session) error) error {
+	var expired []string
+	err := b.Range(func(s *Session) error {
+		if s.ExpiresAt > now {
+			return nil
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+		expired = append(expired, s.ID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return b.Delete(expired...)
+}
+
+// Range : 읽기 트랜잭션 안에서 키(ID) 순으로 순회합니다.
+func (b *BoltStore) Range(fn func(*Session) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).ForEach(func(_, data []byte) error {
+			s, err := decodeSession(data)
+			if err != nil {
+				return err
+			}
+			return fn(s)
+		})
+	})
+}
+
+func (b *BoltStore) Count() (int, error) {
+	n := 0
+	err := b.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(sessionsBucket).Stats().KeyN
+		return nil
+	})
+	return n, err
+}
+
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+func decodeSession(data []byte) (*Session, error) {
+	var r sessionRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("decode session: %w", err)
+	}
+	return r.session()
+}
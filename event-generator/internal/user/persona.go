@@ -0,0 +1,273 @@
+package user
+
+import (
+	"errors"
+	"event-generator/internal/fsm"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+)
+
+// 기본 페르소나 이름
+const (
+	PersonaBargainHunter = "bargain_hunter"
+	PersonaPlanner       = "planner"
+	PersonaImpulseBuyer  = "impulse_buyer"
+	PersonaWindowShopper = "window_shopper"
+	PersonaCrawler       = "crawler"
+)
+
+// =======================
+// Persona
+// =======================
+
+// Persona : 유저 행동 유형
+//
+// Transitions 는 전이 가중치에 곱할 배수이며, 키는 이벤트 이름("purchased") 또는
+// "상태.이벤트"("search.page_viewed") 입니다. 둘 다 있으면 두 배수를 모두 곱합니다.
+// 체류 시간(stay_sec)은 DwellScale 배 한 뒤 표준편차 DwellJitter 의 로그정규 노이즈를 곱합니다.
+// Abandon 은 행동 뒤 다음 행동 없이 화면을 떠나 둘 확률이며, 그 세션은 TTL 이 지나 timeout 으로 끝납니다.
+type Persona struct {
+	Name        string             `json:"name" yaml:"name"`
+	Weight      float64            `json:"weight" yaml:"weight"` // 유저 배정 비율
+	Transitions map[string]float64 `json:"transitions,omitempty" yaml:"transitions,omitempty"`
+	DwellScale  float64            `json:"dwell_scale" yaml:"dwell_scale"`
+	DwellJitter float64            `json:"dwell_jitter" yaml:"dwell_jitter"`
+	Abandon     float64            `json:"abandon" yaml:"abandon"` // 행동마다 방치(이탈 이벤트 없이 만료)할 확률 (0~1)
+
+	byEvent map[fsm.EventType]float64
+	byState map[transitionKey]float64
+}
+
+type transitionKey struct {
+	state fsm.State
+	event fsm.EventType
+}
+
+// DefaultPersonas : 기본 페르소나 구성
+func DefaultPersonas() []Persona {
+	return []Persona{
+		{
+			Name:   PersonaBargainHunter,
+			Weight: 25,
+			Transitions: map[string]float64{
+				"search_submitted":   1.3,
+				"search.page_viewed": 1.5,
+				"remove_from_cart":   1.5,
+				"purchased":          0.8,
+			},
+			DwellScale:  1.2,
+			DwellJitter: 0.3,
+			Abandon:     0.03,
+		},
+		{
+			Name:   PersonaPlanner,
+			Weight: 25,
+			Transitions: map[string]float64{
+				"add_to_cart": 1.5,
+				"cart_viewed": 1.5,
+				"exit":        0.8,
+			},
+			DwellScale:  1.6,
+			DwellJitter: 0.4,
+			Abandon:     0.05,
+		},
+		{
+			Name:   PersonaImpulseBuyer,
+			Weight: 15,
+			Transitions: map[string]float64{
+				"click.purchased": 2.5,
+				"purchased":       1.5,
+				"add_to_cart":     0.7,
+				"back":            0.6,
+			},
+			DwellScale:  0.5,
+			DwellJitter: 0.5,
+			Abandon:     0.02,
+		},
+		{
+			Name:   PersonaWindowShopper,
+			Weight: 30,
+			Transitions: map[string]float64{
+				"category_clicked":   1.4,
+				"event_page_clicked": 1.4,
+				"add_to_cart":        0.6,
+				"purchased":          0.3,
+			},
+			DwellScale:  1.0,
+			DwellJitter: 0.6,
+			Abandon:     0.06,
+		},
+		{
+			Name:   PersonaCrawler,
+			Weight: 5,
+			Transitions: map[string]float64{
+				"product_clicked":    2,
+				"search.page_viewed": 3,
+				"add_to_cart":        0,
+				"purchased":          0,
+				"exit":               0.5,
+			},
+			DwellScale:  0.05,
+			DwellJitter: 0,
+		},
+	}
+}
+
+// ValidatePersonas : 페르소나 설정 검사
+func ValidatePersonas(personas []Persona) error {
+	var errs []error
+
+	if len(personas) == 0 {
+		return errors.New("at least one persona is required")
+	}
+	seen := map[string]bool{}
+	total := 0.0
+	for i, p := range personas {
+		name := p.Name
+		if name == "" {
+			errs = append(errs, fmt.Errorf("[%d]: name is required", i))
+			name = fmt.Sprintf("[%d]", i)
+		} else if seen[name] {
+			errs = append(errs, fmt.Errorf("%s: duplicate persona name", name))
+		}
+		seen[name] = true
+
+		if p.Weight < 0 {
+			errs = append(errs, fmt.Errorf("%s: weight must be >= 0 (got %g)", name, p.Weight))
+		}
+		total += p.Weight
+		if p.DwellScale <= 0 {
+			errs = append(errs, fmt.Errorf("%s: dwell_scale must be > 0 (got %g)", name, p.DwellScale))
+		}
+		if p.DwellJitter < 0 {
+			errs = append(errs, fmt.Errorf("%s: dwell_jitter must be >= 0 (got %g)", name, p.DwellJitter))
+		}
+		if p.Abandon < 0 || p.Abandon > 1 {
+			errs = append(errs, fmt.Errorf("%s: abandon must be between 0 and 1 (got %g)", name, p.Abandon))
+		}
+		for key, m := range p.Transitions {
+			if m < 0 {
+				errs = append(errs, fmt.Errorf("%s: transitions[%s] must be >= 0 (got %g)", name, key, m))
+			}
+			if strings.Count(key, ".") > 1 || strings.HasPrefix(key, ".") || strings.HasSuffix(key, ".") {
+				errs = append(errs, fmt.Errorf("%s: transitions key %q must be \"event\" or \"state.event\"", name, key))
+			}
+		}
+	}
+	if total <= 0 {
+		errs = append(errs, errors.New("persona weights must sum to > 0"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// CheckPersonas : 모델에 없는 상태/이벤트를 가리키는 전이 배수를 경고 목록으로 돌려줍니다.
+func CheckPersonas(personas []Persona, model *fsm.Model) []string {
+	if model == nil {
+		model = fsm.DefaultModel()
+	}
+	states := map[fsm.State]bool{}
+	for _, s := range model.States {
+		states[s] = true
+	}
+	// events 목록은 생략할 수 있으므로 전이에 쓰인 이벤트도 포함
+	events := map[fsm.EventType]bool{}
+	for _, e := range model.Events {
+		events[e] = true
+	}
+	for _, ts := range model.Transitions {
+		for _, t := range ts {
+			events[t.Event] = true
+		}
+	}
+
+	var warnings []string
+	for _, p := range personas {
+		keys := make([]string, 0, len(p.Transitions))
+		for k := range p.Transitions {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			state, ev, scoped := strings.Cut(k, ".")
+			if !scoped {
+				ev, state = state, ""
+			}
+			if scoped && !states[fsm.State(state)] {
+				warnings = append(warnings, fmt.Sprintf("persona %s: transitions[%s] refers to unknown state %q", p.Name, k, state))
+			}
+			if !events[fsm.EventType(ev)] {
+				warnings = append(warnings, fmt.Sprintf("persona %s: transitions[%s] refers to unknown event %q", p.Name, k, ev))
+			}
+		}
+	}
+	return warnings
+}
+
+// compile : 문자열 키를 조회용 맵으로 변환
+func (p *Persona) compile() {
+	p.byEvent = map[fsm.EventType]float64{}
+	p.byState = map[transitionKey]float64{}
+	for k, m := range p.Transitions {
+		if state, ev, scoped := strings.Cut(k, "."); scoped {
+			p.byState[transitionKey{fsm.State(state), fsm.EventType(ev)}] = m
+		} else {
+			p.byEvent[fsm.EventType(k)] = m
+		}
+	}
+}
+
+// multiplier : from 상태에서 ev 전이에 곱할 배수
+func (p *Persona) multiplier(from fsm.State, ev fsm.EventType) float64 {
+	m := 1.0
+	if v, ok := p.byEvent[ev]; ok {
+		m *= v
+	}
+	if v, ok := p.byState[transitionKey{from, ev}]; ok {
+		m *= v
+	}
+	return m
+}
+
+// dwell : 페르소나에 맞춰 체류 시간(초)을 조정합니다. (최소 1초)
+func (p *Persona) dwell(sec int, r *rand.Rand) int {
+	v := float64(sec) * p.DwellScale
+	if p.DwellJitter > 0 {
+		v *= math.Exp(r.NormFloat64() * p.DwellJitter)
+	}
+	return max(int(math.Round(v)), 1)
+}
+
+// =======================
+// Persona set
+// =======================
+
+// personaSet : 이름으로 찾고 비율대로 배정하기 위한 페르소나 모음
+type personaSet struct {
+	list    []*Persona
+	byName  map[string]*Persona
+	weights []float64
+}
+
+func newPersonaSet(personas []Persona) *personaSet {
+	ps := &personaSet{byName: map[string]*Persona{}}
+	for i := range personas {
+		p := personas[i]
+		p.compile()
+		ps.list = append(ps.list, &p)
+		ps.byName[p.Name] = &p
+		ps.weights = append(ps.weights, p.Weight)
+	}
+	return ps
+}
+
+// pick : p(0~1) 위치의 페르소나 (페르소나가 없으면 nil)
+func (ps *personaSet) pick(p float64) *Persona {
+	if len(ps.list) == 0 {
+		return nil
+	}
+	return ps.list[pickWeighted(ps.weights, p)]
+}
@@ -0,0 +1,247 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// 멤버십 등급
+const (
+	TierBasic  = "basic"
+	TierSilver = "silver"
+	TierGold   = "gold"
+	TierVIP    = "vip"
+)
+
+// =======================
+// Profile distributions
+// =======================
+
+// HomeCountry : 유저 거주 국가와 사용 언어, 선택 가중치
+type HomeCountry struct {
+	Country  string  `json:"country" yaml:"country"`   // ISO 3166-1 alpha-2
+	Language string  `json:"language" yaml:"language"` // BCP 47 (예: ko-KR)
+	Weight   float64 `json:"weight" yaml:"weight"`
+}
+
+// ProfileDistribution : 새 유저 프로필을 만들 때 사용하는 분포
+//
+// 가중치 맵은 키 이름 순으로 정렬해서 뽑기 때문에 seed 모드에서도 결과가 재현됩니다.
+type ProfileDistribution struct {
+	HomeCountries []HomeCountry      `json:"home_countries" yaml:"home_countries"`
+	Devices       map[string]float64 `json:"devices" yaml:"devices"`
+	LoyaltyTiers  map[string]float64 `json:"loyalty_tiers" yaml:"loyalty_tiers"`
+	SignupDays    int                `json:"signup_days" yaml:"signup_days"` // 가입일은 기준 시각 이전 N 일 안에서 균등 분포
+
+	// 가격 민감도 (0 = 가격 무관, 1 = 최저가만 구매): 평균/표준편차의 정규분포를 [0, 1] 로 자른 값
+	PriceSensitivityMean   float64 `json:"price_sensitivity_mean" yaml:"price_sensitivity_mean"`
+	PriceSensitivityStddev float64 `json:"price_sensitivity_stddev" yaml:"price_sensitivity_stddev"`
+
+	MaxDestinations int `json:"max_destinations" yaml:"max_destinations"` // 선호 여행지 최대 개수 (카탈로그 국가 중 선택)
+}
+
+// DefaultProfileDistribution : 기본 분포 (한국 중심 여행 이커머스)
+func DefaultProfileDistribution() ProfileDistribution {
+	return ProfileDistribution{
+		HomeCountries: []HomeCountry{
+			{"KR", "ko-KR", 80},
+			{"US", "en-US", 6},
+			{"JP", "ja-JP", 5},
+			{"TW", "zh-TW", 5},
+			{"SG", "en-SG", 4},
+		},
+		Devices: map[string]float64{
+			DeviceIOS:     45,
+			DeviceAndroid: 40,
+			DeviceWeb:     15,
+		},
+		LoyaltyTiers: map[string]float64{
+			TierBasic:  60,
+			TierSilver: 25,
+			TierGold:   12,
+			TierVIP:    3,
+		},
+		SignupDays:             730,
+		PriceSensitivityMean:   0.5,
+		PriceSensitivityStddev: 0.2,
+		MaxDestinations:        3,
+	}
+}
+
+// Validate : 분포 설정 검사
+func (d *ProfileDistribution) Validate() error {
+	var errs []error
+
+	if len(d.HomeCountries) == 0 {
+		errs = append(errs, errors.New("home_countries must not be empty"))
+	}
+	total := 0.0
+	for _, hc := range d.HomeCountries {
+		if hc.Country == "" || hc.Language == "" {
+			errs = append(errs, errors.New("home_countries entries need country and language"))
+		}
+		if hc.Weight < 0 {
+			errs = append(errs, fmt.Errorf("home_countries %s: weight must be >= 0 (got %g)", hc.Country, hc.Weight))
+		}
+		total += hc.Weight
+	}
+	if len(d.HomeCountries) > 0 && total <= 0 {
+		errs = append(errs, errors.New("home_countries weights must sum to > 0"))
+	}
+	if err := validateWeights("devices", d.Devices); err != nil {
+		errs = append(errs, err)
+	}
+	for device := range d.Devices {
+		switch device {
+		case DeviceIOS, DeviceAndroid, DeviceWeb:
+		default:
+			errs = append(errs, fmt.Errorf("devices: unknown device %q (use ios, android, web)", device))
+		}
+	}
+	if err := validateWeights("loyalty_tiers", d.LoyaltyTiers); err != nil {
+		errs = append(errs, err)
+	}
+	for tier := range d.LoyaltyTiers {
+		if _, ok := tierBias[tier]; !ok {
+			errs = append(errs, fmt.Errorf("loyalty_tiers: unknown tier %q (use basic, silver, gold, vip)", tier))
+		}
+	}
+	if d.SignupDays <= 0 {
+		errs = append(errs, fmt.Errorf("signup_days must be > 0 (got %d)", d.SignupDays))
+	}
+	if d.PriceSensitivityMean < 0 || d.PriceSensitivityMean > 1 {
+		errs = append(errs, fmt.Errorf("price_sensitivity_mean must be in [0, 1] (got %g)", d.PriceSensitivityMean))
+	}
+	if d.PriceSensitivityStddev < 0 {
+		errs = append(errs, fmt.Errorf("price_sensitivity_stddev must be >= 0 (got %g)", d.PriceSensitivityStddev))
+	}
+	if d.MaxDestinations < 0 {
+		errs = append(errs, fmt.Errorf("max_destinations must be >= 0 (got %d)", d.MaxDestinations))
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateWeights(name string, weights map[string]float64) error {
+	if len(weights) == 0 {
+		return fmt.Errorf("%s must not be empty", name)
+	}
+	total := 0.0
+	for k, w := range weights {
+		if w < 0 {
+			return fmt.Errorf("%s %s: weight must be >= 0 (got %g)", name, k, w)
+		}
+		total += w
+	}
+	if total <= 0 {
+		return fmt.Errorf("%s weights must sum to > 0", name)
+	}
+	return nil
+}
+
+// =======================
+// Profile generation
+// =======================
+
+// newProfile : 분포에 따라 유저 프로필 한 명을 만듭니다.
+// intN / float 은 UserPool 의 난수 스트림이고, destinations 는 카탈로그 국가 목록입니다.
+func (d *ProfileDistribution) newProfile(id string, now time.Time, destinations []string, intN func(int) int, float func() float64) *User {
+	u := &User{ID: id}
+
+	weights := make([]float64, len(d.HomeCountries))
+	for i, hc := range d.HomeCountries {
+		weights[i] = hc.Weight
+	}
+	hc := d.HomeCountries[pickWeighted(weights, float())]
+	u.HomeCountry = hc.Country
+	u.Language = hc.Language
+
+	u.Device = pickWeightedKey(d.Devices, float())
+	u.LoyaltyTier = pickWeightedKey(d.LoyaltyTiers, float())
+
+	signup := now.AddDate(0, 0, -intN(d.SignupDays))
+	u.SignupDate = signup.UTC().Format(time.DateOnly)
+
+	// Box-Muller 로 정규분포 근사 (rand.NormFloat64 는 *rand.Rand 에만 있으므로 직접 계산)
+	z := math.Sqrt(-2*math.Log(1-float())) * math.Cos(2*math.Pi*float())
+	ps := d.PriceSensitivityMean + z*d.PriceSensitivityStddev
+	u.PriceSensitivity = math.Round(min(max(ps, 0), 1)*100) / 100
+
+	if n := min(d.MaxDestinations, len(destinations)); n > 0 {
+		k := intN(n) + 1
+		picked := slices.Clone(destinations)
+		// 앞에서부터 k 개만 섞어서 뽑기 (부분 Fisher-Yates)
+		for i := 0; i < k; i++ {
+			j := i + intN(len(picked)-i)
+			picked[i], picked[j] = picked[j], picked[i]
+		}
+		u.PreferredDestinations = picked[:k]
+	}
+
+	return u
+}
+
+// pickWeighted : 누적 가중치에서 p(0~1) 위치의 인덱스
+func pickWeighted(weights []float64, p float64) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	target := p * total
+	acc := 0.0
+	for i, w := range weights {
+		acc += w
+		if target < acc {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
+
+// pickWeightedKey : 키 이름 순으로 정렬한 가중치 맵에서 하나 선택
+func pickWeightedKey(m map[string]float64, p float64) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	weights := make([]float64, len(keys))
+	for i, k := range keys {
+		weights[i] = m[k]
+	}
+	return keys[pickWeighted(weights, p)]
+}
+
+// =======================
+// Behavior bias
+// =======================
+
+// 멤버십 등급별 구매 성향 (전이 가중치 배수)
+var tierBias = map[string]float64{
+	TierBasic:  1.0,
+	TierSilver: 1.15,
+	TierGold:   1.3,
+	TierVIP:    1.5,
+}
+
+// purchaseBias : 등급이 높고 가격에 덜 민감하며 재방문한 유저일수록 구매 확률이 높아집니다.
+func (u *User) purchaseBias(sessionNumber int) float64 {
+	bias := tierBias[u.LoyaltyTier]
+	if bias == 0 {
+		bias = 1
+	}
+	bias *= 1.2 - 0.4*u.PriceSensitivity
+	if sessionNumber > 1 {
+		bias *= 1.1
+	}
+	return bias
+}
+
+// exitBias : 가격에 민감한 유저는 더 쉽게 이탈합니다.
+func (u *User) exitBias() float64 {
+	return 0.8 + 0.4*u.PriceSensitivity
+}
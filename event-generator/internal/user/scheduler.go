@@ -0,0 +1,72 @@
+package user
+
+import "container/heap"
+
+// =======================
+// Scheduler
+// =======================
+
+// scheduled : 다음 행동 시각이 정해진 세션
+type scheduled struct {
+	at      int64  // 다음 이벤트 시각 (epoch millis)
+	seq     uint64 // 같은 시각이면 먼저 예약한 세션부터 (seed 모드 재현성)
+	session *Session
+	timeout bool // 다음 행동 전에 TTL 이 지나므로 at(=ExpiresAt) 에 세션을 만료시킴
+}
+
+// scheduler : 다음 행동 시각 기준 최소 힙
+// 동시성 보호는 sessionShard 의 뮤텍스가 담당합니다.
+type scheduler struct {
+	items []scheduled
+	seq   uint64
+}
+
+func (q *scheduler) Len() int { return len(q.items) }
+
+func (q *scheduler) Less(i, j int) bool {
+	if q.items[i].at != q.items[j].at {
+		return q.items[i].at < q.items[j].at
+	}
+	return q.items[i].seq < q.items[j].seq
+}
+
+func (q *scheduler) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *scheduler) Push(x any) { q.items = append(q.items, x.(scheduled)) }
+
+func (q *scheduler) Pop() any {
+	n := len(q.items)
+	it := q.items[n-1]
+	q.items[n-1] = scheduled{}
+	q.items = q.items[:n-1]
+	return it
+}
+
+// schedule : 세션의 다음 행동을 at 시각에 예약하고 예약 순번을 반환합니다. (timeout 이면 at 에 만료)
+func (q *scheduler) schedule(s *Session, at int64, timeout bool) uint64 {
+	q.seq++
+	heap.Push(q, scheduled{at: at, seq: q.seq, session: s, timeout: timeout})
+	return q.seq
+}
+
+// restore : 저장해 둔 순번 seq 로 다시 예약합니다. 이후의 예약은 복원한 순번보다 뒤에 섭니다.
+func (q *scheduler) restore(s *Session, at int64, seq uint64, timeout bool) {
+	q.seq = max(q.seq, seq)
+	heap.Push(q, scheduled{at: at, seq: seq, session: s, timeout: timeout})
+}
+
+// popDue : now 이전에 예약된 가장 이른 세션을 꺼냅니다.
+func (q *scheduler) popDue(now int64) (scheduled, bool) {
+	if len(q.items) == 0 || q.items[0].at > now {
+		return scheduled{}, false
+	}
+	return heap.Pop(q).(scheduled), true
+}
+
+// earliest : 가장 이른 예약 시각 (비어 있으면 false)
+func (q *scheduler) earliest() (int64, bool) {
+	if len(q.items) == 0 {
+		return 0, false
+	}
+	return q.items[0].at, true
+}
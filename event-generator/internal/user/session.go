@@ -1,17 +1,25 @@
 package user
 
 import (
+	"event-generator/internal/event"
 	"event-generator/internal/fsm"
+	"event-generator/internal/rng"
+	"math/rand/v2"
 	"sync"
 	"time"
 )
 
 // Session : 세션 기본 구조체
+//
+// 세션은 한 번에 한 고루틴만 진행합니다. SessionManager 가 스케줄러에서 꺼내거나 새로 만든 고루틴이
+// 다시 예약하거나 삭제할 때까지 세션을 소유하며, 그동안 mu 를 잡아 같은 세션의 이벤트가 차례대로 만들어지게 합니다.
+// SessionManager 밖에서 필드를 읽을 때도 Lock / Unlock 으로 감싸야 합니다.
 type Session struct {
 	ID                      string
 	UserID                  string
 	State                   fsm.State
 	PrevState               fsm.State
+	BackState               fsm.State // back 이벤트로 돌아갈 화면
 	PageType                string
 	EventPage               string
 	BrowsingCountryCategory string
@@ -25,50 +33,44 @@ type Session struct {
 	LastCategory            string
 	LastCountry             string
 	LastQuantity            int
+	LastVendor              string
+	Cart                    []fsm.CartLine
+	LastOrder               []fsm.CartLine // 마지막 결제 줄 (purchased 이벤트 기록용)
+	Device                  string         // 유저의 접속 기기 (세션 생성 시 User 에서 복사)
+	Referrer                string         // 세션 유입 경로 (첫 이벤트에서 결정)
+	Profile                 *User          // 세션 주인의 프로필 (읽기 전용)
+	SessionNumber           int            // 유저의 몇 번째 세션인지 (1 부터)
+	Persona                 *Persona       // 행동 유형 (전이 배수 / 체류 시간)
+	StartedAt               int64          // 세션 시작 시각 (epoch millis)
+	EventCount              int            // 내보낸 행동 이벤트 수 (생명주기 이벤트 제외)
+	Purchased               bool           // 세션 중 구매 여부 (session_end 사유 구분)
+
+	// 다음 행동 예약 (SessionStore 저장 / 복원용, 스케줄러에 넣을 때 갱신)
+	NextAt      int64  // 다음 행동 시각 (epoch millis)
+	NextTimeout bool   // NextAt 에 행동 대신 TTL 만료로 종료
+	NextSeq     uint64 // 같은 시각에 예약된 세션 사이의 순서 (복원 후에도 같은 순서로 진행)
+
+	src *rand.PCG  // 세션 전용 난수 소스 (상태 저장용)
+	rng *rand.Rand // src 를 사용하는 난수 스트림
+	mu  sync.Mutex // 소유 락 (Step 이 세션을 진행하는 동안 잡음)
 }
 
-// 전역 세션 저장소
-var sessionStore = make(map[string]*Session)
-var mu sync.Mutex // 동시성 문제 해결을 위한 뮤텍스
-
 // NewSession
-func NewSession(sessionID, userID string, ttl time.Duration) *Session {
-	now := time.Now().UnixMilli()
+// now 는 epoch millis, src 는 세션 전용 난수 소스 (nil 이면 비결정 소스 생성)
+func NewSession(sessionID, userID string, now int64, ttl time.Duration, src *rand.PCG) *Session {
+	if src == nil {
+		src = rng.RandomSource()
+	}
 	return &Session{
 		ID:          sessionID,
 		UserID:      userID,
 		State:       fsm.StateBrowsing, // 초기 상태
 		LastEventTs: now,
-		ExpiresAt:   time.Now().Add(ttl).UnixMilli(),
-	}
-}
-
-// GetSession
-func GetSession(sessionID, userID string, ttl time.Duration) *Session {
-	mu.Lock()
-	defer mu.Unlock()
-
-	// 세션이 이미 존재하면 반환
-	if session, exists := sessionStore[sessionID]; exists {
-		// 세션이 만료되었으면 새로 생성
-		if time.Now().UnixMilli() > session.ExpiresAt {
-			session = NewSession(sessionID, userID, ttl)
-			sessionStore[sessionID] = session
-		}
-		return session
+		StartedAt:   now,
+		ExpiresAt:   now + ttl.Milliseconds(),
+		src:         src,
+		rng:         rand.New(src),
 	}
-
-	// 세션이 없으면 새로 생성
-	session := NewSession(sessionID, userID, ttl)
-	sessionStore[sessionID] = session
-	return session
-}
-
-// 세션 종료 (삭제)
-func DeleteSession(sessionID string) {
-	mu.Lock()
-	defer mu.Unlock()
-	delete(sessionStore, sessionID)
 }
 
 // 세션 인터페이스 구현
@@ -81,6 +83,21 @@ func (s *Session) GetUserID() string {
 	return s.UserID
 }
 
+// ===== ownership =====
+// Lock : 세션 소유 락 (Step 이 진행 중이면 끝날 때까지 대기)
+func (s *Session) Lock() {
+	s.mu.Lock()
+}
+
+func (s *Session) Unlock() {
+	s.mu.Unlock()
+}
+
+// ===== random =====
+func (s *Session) Rand() *rand.Rand {
+	return s.rng
+}
+
 // ===== state =====
 func (s *Session) GetState() fsm.State {
 	return s.State
@@ -98,6 +115,14 @@ func (s *Session) SetPrevState(state fsm.State) {
 	s.PrevState = state
 }
 
+func (s *Session) GetBackState() fsm.State {
+	return s.BackState
+}
+
+func (s *Session) SetBackState(state fsm.State) {
+	s.BackState = state
+}
+
 // ===== time =====
 func (s *Session) GetLastEventTs() int64 {
 	return s.LastEventTs
@@ -192,6 +217,14 @@ func (s *Session) GetLastCategory() string {
 	return s.LastCategory
 }
 
+func (s *Session) SetLastVendor(vendor string) {
+	s.LastVendor = vendor
+}
+
+func (s *Session) GetLastVendor() string {
+	return s.LastVendor
+}
+
 func (s *Session) SetLastQuantity(qty int) {
 	s.LastQuantity = qty
 }
@@ -199,3 +232,101 @@ func (s *Session) SetLastQuantity(qty int) {
 func (s *Session) GetLastQuantity() int {
 	return s.LastQuantity
 }
+
+// ===== cart =====
+func (s *Session) GetCart() []fsm.CartLine {
+	return s.Cart
+}
+
+func (s *Session) SetCart(lines []fsm.CartLine) {
+	s.Cart = lines
+}
+
+func (s *Session) GetLastOrder() []fsm.CartLine {
+	return s.LastOrder
+}
+
+func (s *Session) SetLastOrder(lines []fsm.CartLine) {
+	s.LastOrder = lines
+}
+
+// ===== acquisition =====
+func (s *Session) GetDevice() string {
+	return s.Device
+}
+
+func (s *Session) GetReferrer() string {
+	return s.Referrer
+}
+
+func (s *Session) SetReferrer(referrer string) {
+	s.Referrer = referrer
+}
+
+// ===== profile =====
+func (s *Session) GetPreferredDestinations() []string {
+	if s.Profile == nil {
+		return nil
+	}
+	return s.Profile.PreferredDestinations
+}
+
+func (s *Session) GetPriceSensitivity() float64 {
+	if s.Profile == nil {
+		return 0
+	}
+	return s.Profile.PriceSensitivity
+}
+
+// GetPersona : 페르소나 이름 (없으면 빈 문자열)
+func (s *Session) GetPersona() string {
+	if s.Persona == nil {
+		return ""
+	}
+	return s.Persona.Name
+}
+
+// EventBias : 프로필과 페르소나에 따라 from 상태의 ev 전이 가중치에 곱할 배수 (둘 다 없으면 1)
+func (s *Session) EventBias(from fsm.State, ev fsm.EventType) float64 {
+	bias := 1.0
+	if s.Profile != nil {
+		switch ev {
+		case fsm.EventPurchased:
+			bias *= s.Profile.purchaseBias(s.SessionNumber)
+		case fsm.EventExit:
+			bias *= s.Profile.exitBias()
+		}
+	}
+	if s.Persona != nil {
+		bias *= s.Persona.multiplier(from, ev)
+	}
+	return bias
+}
+
+// UserInfo : 이벤트에 싣는 유저 프로필 요약 (프로필이 없으면 nil)
+func (s *Session) UserInfo() *event.UserInfo {
+	p := s.Profile
+	if p == nil {
+		return nil
+	}
+	return &event.UserInfo{
+		HomeCountry:   p.HomeCountry,
+		Language:      p.Language,
+		LoyaltyTier:   p.LoyaltyTier,
+		SignupDate:    p.SignupDate,
+		SessionNumber: s.SessionNumber,
+	}
+}
+
+// Abandons : 이번 행동 뒤 다음 행동 없이 세션을 방치하는지 (페르소나의 Abandon 확률)
+func (s *Session) Abandons() bool {
+	return s.Persona != nil && s.Persona.Abandon > 0 && s.rng.Float64() < s.Persona.Abandon
+}
+
+// Dwell : 페르소나의 체류 시간 분포에 맞춰 stay_sec 를 조정합니다.
+func (s *Session) Dwell(sec int) int {
+	if s.Persona == nil {
+		return sec
+	}
+	return s.Persona.dwell(sec, s.rng)
+}
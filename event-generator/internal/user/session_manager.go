@@ -1,11 +1,13 @@
 package user
 
 import (
+	"event-generator/internal/clock"
 	"event-generator/internal/event"
 	"event-generator/internal/fsm"
 	"event-generator/internal/metrics"
+	"event-generator/internal/rng"
 	"fmt"
-	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,6 +16,18 @@ import (
 // =======================
 type PayloadGenerator interface {
 	Generate(eventType string, session *Session) map[string]any
+	// Populate : 이벤트의 타입이 정해진 속성(Product, Device, Referrer, Page, Query)을 채웁니다.
+	Populate(ev *event.Event, session *Session)
+	// Page : 세션의 현재 상태에서 보고 있는 화면 (Populate 가 채우는 Page 와 같은 규칙)
+	Page(session *Session) string
+}
+
+// =======================
+// EventQueue Interface
+// =======================
+// SessionManager → Worker 이벤트 큐 (채널이 가득 찼을 때의 처리는 구현체의 backpressure 정책을 따름)
+type EventQueue interface {
+	Push(ev *event.Event)
 }
 
 // =======================
@@ -23,15 +37,18 @@ type SessionManager struct {
 	userPool   *UserPool
 	fsm        fsm.FSM
 	payloadGen PayloadGenerator
-	eventChan  chan *event.Event
+	events     EventQueue
 	metrics    metrics.Metrics
+	clock      clock.Clock
+	rngs       *rng.Factory
 
-	// [수정] 맵 구조 개선: userID로 세션ID를 즉시 찾기 위한 인덱스 추가
-	sessions      map[string]*Session // key: sessionID
-	userToSession map[string]string   // key: userID, value: sessionID
-	ttl           time.Duration
+	ttl time.Duration
 
-	mu sync.RWMutex // [수정] 읽기 성능 향상을 위해 RWMutex 사용
+	// 세션 저장소 / 스케줄은 userID 기준으로 조각내어 Step 고루틴끼리 같은 락을 잡지 않게 합니다.
+	shards []*sessionShard
+	cursor atomic.Uint64 // 예약 세션을 꺼낼 조각을 돌아가며 고르는 커서
+
+	generated atomic.Int64 // 큐로 내보낸 이벤트 수 (backpressure 정책으로 버린 이벤트 포함)
 }
 
 // =======================
@@ -41,136 +58,466 @@ func NewSessionManager(
 	userPool *UserPool,
 	fsm fsm.FSM,
 	payloadGen PayloadGenerator,
-	eventChan chan *event.Event,
+	events EventQueue,
 	metricStore metrics.Metrics,
 	ttl time.Duration,
+	shards int,
+	clk clock.Clock,
+	rngs *rng.Factory,
 ) *SessionManager {
 	sm := &SessionManager{
-		userPool:      userPool,
-		fsm:           fsm,
-		payloadGen:    payloadGen,
-		eventChan:     eventChan,
-		metrics:       metricStore,
-		sessions:      make(map[string]*Session),
-		userToSession: make(map[string]string), // 맵 초기화
-		ttl:           ttl,
+		userPool:   userPool,
+		fsm:        fsm,
+		payloadGen: payloadGen,
+		events:     events,
+		metrics:    metricStore,
+		clock:      clk,
+		rngs:       rngs,
+		ttl:        ttl,
+		shards:     make([]*sessionShard, max(shards, 1)),
+	}
+	for i := range sm.shards {
+		sm.shards[i] = newSessionShard()
 	}
-
-	// [수정] 매 Step마다 하던 청소를 별도 고루틴으로 분리 (워커 부하 감소)
-	go sm.backgroundCleanup()
 
 	return sm
 }
 
+// 새 세션을 시작할 때 이미 세션이 있는 유저를 뽑으면 다시 뽑는 최대 횟수
+const maxPickAttempts = 8
+
+// stay_sec 이 없는 이벤트의 기본 체류 시간 (초)
+const defaultDwellSec = 10
+
 // =======================
 // Public API
 // =======================
-func (sm *SessionManager) Step() {
-	now := time.Now().UnixMilli()
-	u := sm.userPool.GetRandomUser()
-	if u == nil {
-		return
+// Step : 다음 행동 시각이 된 세션을 한 단계 진행시키고 이벤트를 큐로 보냅니다.
+// 예약된 세션이 없으면 쉬고 있는 유저의 새 세션을 시작합니다.
+// 이벤트 시각은 예약 시각이므로 한 세션의 이벤트 간격은 직전 이벤트의 stay_sec 과 일치합니다.
+//
+// 세션의 첫 이벤트 앞에는 session_start, 종료 상태에 도달하거나 TTL 이 지나면 session_end 를 함께 내보내므로
+// 한 번의 Step 에서 최대 3개의 이벤트가 나갈 수 있습니다. 내보낸 이벤트 수를 반환합니다.
+func (sm *SessionManager) Step() int {
+	now := sm.clock.Now().UnixMilli()
+
+	// 1. 예약된 세션 꺼내기, 없으면 새 세션 시작
+	s, at, timeout := sm.nextDue(now)
+	if s == nil {
+		if s = sm.startSession(); s == nil {
+			return 0
+		}
+		at = s.StartedAt
+	}
+
+	// 세션 소유: 꺼내거나 만든 고루틴만 세션을 진행하며, 다시 예약 / 삭제할 때까지 소유 락을 잡음
+	// (락 순서는 세션 → 조각. 조각 락을 잡은 채로 세션 락을 잡지 않음)
+	s.Lock()
+	defer s.Unlock()
+
+	// 다음 행동 전에 TTL 이 지난 세션은 만료 시각에 session_end(timeout) 로 종료
+	if timeout {
+		sm.endSession(s, at, event.EndReasonTimeout)
+		return 1
 	}
 
-	// 1. 세션 조회/생성 (O(1) 성능)
-	s := sm.getOrCreateSession(u.ID, now)
+	// 세션의 진입 화면은 첫 전이 전 (초기 상태) 의 화면
+	var entryPage string
+	if s.EventCount == 0 {
+		entryPage = sm.payloadGen.Page(s)
+	}
 
 	// 2. FSM 상태 전이
-	ev := sm.fsm.Step(s, now)
+	ev := sm.fsm.Step(s, at)
 	if ev == nil {
-		// ev가 nil이면 세션이 종료되었거나 유효하지 않은 상태
-		return
+		// 나갈 전이가 없는 상태면 세션 종료
+		if s.EventCount == 0 {
+			// 아무 이벤트도 내보내지 않은 세션은 시작 / 종료 이벤트 없이 정리
+			sm.deleteSession(s.UserID, s.ID)
+			return 0
+		}
+		sm.endSession(s, at, sm.endReason(s))
+		return 1
 	}
+	s.ExpiresAt = at + sm.ttl.Milliseconds()
 
 	// 상태 전환 및 메트릭 기록
 	if ev.Attributes.PrevState != ev.Attributes.State {
 		sm.metrics.IncStateTransition(ev.Attributes.PrevState, ev.Attributes.State)
 	}
 
-	if ev.EventType == string(fsm.EventSearchSubmitted) {
-		sm.metrics.IncSessionStart()
-	}
-
 	// 페이로드 생성 및 병합
 	payload := sm.payloadGen.Generate(ev.EventType, s)
 	if ev.Attributes.Extra == nil {
 		ev.Attributes.Extra = make(map[string]any)
 	}
+	// 체류 시간: 페르소나 분포에 맞춰 조정하고, 세션이 이어지면 다음 이벤트까지의 간격으로 사용
+	terminal := sm.fsm.IsTerminal(s.GetState())
+	if payload == nil {
+		payload = make(map[string]any)
+	}
+	sec, ok := payload["stay_sec"].(int)
+	if !ok && !terminal {
+		sec, ok = s.rng.IntN(defaultDwellSec)+1, true
+	}
+	if ok {
+		sec = s.Dwell(sec)
+		payload["stay_sec"] = sec
+	}
 	for k, v := range payload {
 		ev.Attributes.Extra[k] = v
 	}
+	sm.payloadGen.Populate(ev, s)
+
+	if persona := s.GetPersona(); persona != "" {
+		sm.metrics.IncPersonaEvent(persona)
+		if ev.EventType == string(fsm.EventPurchased) {
+			sm.metrics.IncPersonaConversion(persona)
+		}
+	}
+	if ev.EventType == string(fsm.EventPurchased) {
+		s.Purchased = true
+	}
+
+	// 3. 큐 전송 (세션의 첫 이벤트면 진입 화면 / 유입 경로가 정해진 뒤 session_start 를 먼저 보냄)
+	emitted := 1
+	if s.EventCount == 0 {
+		sm.emit(sm.sessionStartEvent(s, ev, entryPage))
+		emitted++
+	}
+	s.EventCount++
+	sm.emit(ev)
 
-	// 3. 채널 전송
-	sm.eventChan <- ev
+	// 4. 종료 상태면 session_end 후 삭제, 아니면 체류 시간 뒤로 다음 행동 예약 (초 단위 stay_sec + 밀리초 지터)
+	// 방치하거나 다음 행동이 TTL 보다 늦으면 만료 시각에 timeout 으로 종료하도록 예약
+	if terminal {
+		sm.endSession(s, at, sm.endReason(s))
+		return emitted + 1
+	}
+	next := at + int64(sec)*1000 + int64(s.rng.IntN(1000))
+	if next >= s.ExpiresAt || s.Abandons() {
+		sm.reschedule(s, s.ExpiresAt, true)
+	} else {
+		sm.reschedule(s, next, false)
+	}
+
+	return emitted
+}
+
+// ScheduleLag : 가장 오래 기다린 예약 세션이 예정 시각보다 늦어진 정도 (생성 속도가 부족하면 커짐)
+func (sm *SessionManager) ScheduleLag() time.Duration {
+	now := sm.clock.Now().UnixMilli()
+	earliest := now
+	for _, sh := range sm.shards {
+		sh.mu.Lock()
+		if at, ok := sh.schedule.earliest(); ok && at < earliest {
+			earliest = at
+		}
+		sh.mu.Unlock()
+	}
+	return time.Duration(now-earliest) * time.Millisecond
+}
 
-	// 4. 종료 이벤트인 경우 즉시 삭제
-	if ev.EventType == string(fsm.EventExit) {
-		sm.deleteSession(u.ID, s.ID)
-		sm.metrics.IncSessionComplete()
+// ActiveSessions : 현재 메모리에 있는 세션 수
+func (sm *SessionManager) ActiveSessions() int {
+	n := 0
+	for _, sh := range sm.shards {
+		sh.mu.Lock()
+		n += len(sh.sessions)
+		sh.mu.Unlock()
 	}
+	return n
+}
+
+// Generated : 지금까지 큐로 내보낸 이벤트 수
+func (sm *SessionManager) Generated() int64 {
+	return sm.generated.Load()
+}
+
+// ManagerState : 체크포인트에 저장하는 SessionManager 카운터 (세션은 Save / Restore 로 따로 저장)
+type ManagerState struct {
+	Generated int64  `json:"generated"` // 지금까지 내보낸 이벤트 수
+	Cursor    uint64 `json:"cursor"`    // 예약 세션을 꺼낼 조각 커서 (시드 모드에서 조각 순서를 이어가기 위해 저장)
+}
+
+// State : 현재 카운터 (생성을 멈춘 뒤 호출하면 Save 와 같은 시점의 값)
+func (sm *SessionManager) State() ManagerState {
+	return ManagerState{Generated: sm.generated.Load(), Cursor: sm.cursor.Load()}
+}
 
+// SetState : 체크포인트의 카운터로 이어서 셉니다. 생성을 시작하기 전에 호출합니다.
+func (sm *SessionManager) SetState(st ManagerState) {
+	sm.generated.Store(st.Generated)
+	sm.cursor.Store(st.Cursor)
+}
+
+// =======================
+// Persistence
+// =======================
+
+// Save : 진행 중인 세션을 모두 store 에 저장하고, store 에만 남아 있는 (이미 끝난) 세션은 지웁니다.
+// 진행 중인 Step 은 세션 소유 락으로 끝날 때까지 기다리므로 세션마다 온전한 상태가 저장되며,
+// 생성을 멈춘 뒤 호출하면 전체가 한 시점의 상태가 됩니다. 저장한 세션 수를 반환합니다.
+func (sm *SessionManager) Save(store SessionStore) (int, error) {
+	var live []*Session
+	for _, sh := range sm.shards {
+		sh.mu.Lock()
+		for _, s := range sh.sessions {
+			live = append(live, s)
+		}
+		sh.mu.Unlock()
+	}
+
+	snaps := make([]*Session, 0, len(live))
+	keep := make(map[string]bool, len(live))
+	for _, s := range live {
+		s.Lock()
+		// 락을 기다리는 동안 끝난 세션과 첫 Step 이 아직 끝나지 않은 (예약 전) 세션은 건너뜀
+		var (
+			snap *Session
+			err  error
+		)
+		if sm.holds(s) && s.NextAt != 0 {
+			snap, err = s.snapshot()
+		}
+		s.Unlock()
+		if err != nil {
+			return 0, err
+		}
+		if snap != nil {
+			snaps = append(snaps, snap)
+			keep[snap.ID] = true
+		}
+	}
+	if err := store.Put(snaps...); err != nil {
+		return 0, fmt.Errorf("save sessions: %w", err)
+	}
+
+	var stale []string
+	err := store.Range(func(s *Session) error {
+		if !keep[s.ID] {
+			stale = append(stale, s.ID)
+		}
+		return nil
+	})
+	if err == nil {
+		err = store.Delete(stale...)
+	}
+	if err != nil {
+		return len(snaps), fmt.Errorf("save sessions: %w", err)
+	}
+	return len(snaps), nil
+}
+
+// Restore : store 의 세션을 불러와 저장된 다음 행동 예약부터 이어서 진행합니다. 생성을 시작하기 전에 호출합니다.
+// 예약 시각과 같은 시각 사이의 순서까지 그대로 복원하므로, 꺼져 있는 동안 예약 시각이 지난 세션은
+// 생성이 밀렸을 때와 같이 밀린 행동부터 차례로 진행하고 TTL 만료가 예약된 세션은 만료 시각에 session_end(timeout) 로 닫힙니다.
+// 유저 풀에 없는 유저의 세션과 이미 진행 중인 세션이 있는 유저의 세션은 건너뜁니다.
+func (sm *SessionManager) Restore(store SessionStore) (restored, skipped int, err error) {
+	err = store.Range(func(s *Session) error {
+		if sm.adopt(s) {
+			restored++
+		} else {
+			skipped++
+		}
+		return nil
+	})
+	if err != nil {
+		return restored, skipped, fmt.Errorf("restore sessions: %w", err)
+	}
+	return restored, skipped, nil
+}
+
+// adopt : 저장소에서 불러온 세션을 유저 프로필 / 페르소나에 다시 연결하고 저장된 예약대로 스케줄러에 넣습니다.
+func (sm *SessionManager) adopt(s *Session) bool {
+	u, ok := sm.userPool.Get(s.UserID)
+	if !ok {
+		return false
+	}
+	sh := sm.shardFor(s.UserID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if _, busy := sh.userToSession[s.UserID]; busy {
+		return false
+	}
+
+	s.Profile = u
+	s.Persona = sm.userPool.Persona(u)
+	sh.sessions[s.ID] = s
+	sh.userToSession[s.UserID] = s.ID
+	if s.NextSeq == 0 {
+		// 순번 없이 저장된 세션 (이전 형식)
+		s.NextSeq = sh.schedule.schedule(s, s.NextAt, s.NextTimeout)
+	} else {
+		sh.schedule.restore(s, s.NextAt, s.NextSeq, s.NextTimeout)
+	}
+	return true
 }
 
 // =======================
 // Internal helpers
 // =======================
 
-func (sm *SessionManager) getOrCreateSession(userID string, now int64) *Session {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
+// nextDue : now 까지 예약된 세션과 예약 시각, 그 예약이 TTL 만료인지 여부
+// 조각을 커서 위치부터 돌아가며 살펴 처음으로 예약이 된 조각의 가장 이른 세션을 꺼냅니다.
+// (조각 사이의 순서는 보장하지 않으므로 이벤트 시각은 조각 수 × Step 간격 정도까지 앞뒤가 바뀔 수 있음)
+func (sm *SessionManager) nextDue(now int64) (*Session, int64, bool) {
+	n := len(sm.shards)
+	start := int(sm.cursor.Add(1) % uint64(n))
+	for i := range n {
+		if it, ok := sm.shards[(start+i)%n].popDue(now); ok {
+			return it.session, it.at, it.timeout
+		}
+	}
+	return nil, 0, false
+}
 
-	// userID 인덱스를 통해 O(1)로 조회
-	if sid, ok := sm.userToSession[userID]; ok {
-		if s, exists := sm.sessions[sid]; exists && s.State != fsm.StateExit {
-			s.LastEventTs = now
-			s.ExpiresAt = now + sm.ttl.Milliseconds()
+// startSession : 진행 중인 세션이 없는 유저를 골라 새 세션을 시작합니다.
+// 뽑은 유저마다 이미 세션이 있으면 nil 을 반환합니다.
+func (sm *SessionManager) startSession() *Session {
+	for range maxPickAttempts {
+		u := sm.userPool.GetRandomUser()
+		if u == nil {
+			return nil
+		}
+		if s := sm.createSession(u); s != nil {
 			return s
 		}
 	}
+	return nil
+}
+
+// createSession : u 의 새 세션을 만듭니다. 진행 중인 세션이 있으면 nil 을 반환합니다.
+// 시작 시각은 조각 락을 잡은 뒤에 읽으므로, 다른 고루틴이 방금 끝낸 이전 세션의 session_end 보다 앞서지 않습니다.
+func (sm *SessionManager) createSession(u *User) *Session {
+	userID := u.ID
+	sh := sm.shardFor(userID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	// userID 인덱스를 통해 O(1)로 조회
+	// 세션은 종료 상태 / TTL 만료 시 Step 에서 session_end 와 함께 삭제되므로, 남아 있으면 아직 진행 중입니다.
+	if sid, ok := sh.userToSession[userID]; ok {
+		if _, exists := sh.sessions[sid]; exists {
+			return nil
+		}
+	}
 
 	// 기존 세션이 없으면 새로 생성
+	now := sm.clock.Now().UnixMilli()
 	sessionID := fmt.Sprintf("sess_%s_%d", userID, now)
-	s := NewSession(sessionID, userID, sm.ttl)
-	s.SetState(fsm.StateBrowsing)
+	s := NewSession(sessionID, userID, now, sm.ttl, sm.rngs.Source(sessionID))
+	s.SetState(sm.fsm.InitialState())
+	s.Device = u.Device
+	s.Profile = u
+	s.SessionNumber = sm.userPool.RecordVisit(u, now)
+	s.Persona = sm.userPool.Persona(u)
 
-	sm.sessions[sessionID] = s
-	sm.userToSession[userID] = sessionID
-
-	if sm.metrics != nil {
-		sm.metrics.IncSessionStart()
-	}
+	sh.sessions[sessionID] = s
+	sh.userToSession[userID] = sessionID
 
 	return s
 }
 
 // 세션 명시적 삭제
 func (sm *SessionManager) deleteSession(userID, sessionID string) {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-	delete(sm.sessions, sessionID)
-	delete(sm.userToSession, userID)
-}
-
-// 백그라운드 세션 청소 (워커들의 락 경합 방지)
-func (sm *SessionManager) backgroundCleanup() {
-	ticker := time.NewTicker(2 * time.Second) // 2초마다 수행
-	for range ticker.C {
-		now := time.Now().UnixMilli()
-		sm.mu.Lock()
-		for sid, s := range sm.sessions {
-			if s.ExpiresAt <= now {
-				delete(sm.userToSession, s.UserID)
-				delete(sm.sessions, sid)
-				if sm.metrics != nil {
-					sm.metrics.IncSessionComplete()
-				}
-			}
+	sh := sm.shardFor(userID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	delete(sh.sessions, sessionID)
+	delete(sh.userToSession, userID)
+}
+
+// reschedule : 세션의 다음 행동을 at 시각에 예약합니다. (timeout 이면 at 에 만료)
+// 예약은 세션에도 기록해 SessionStore 에 저장할 수 있게 합니다.
+func (sm *SessionManager) reschedule(s *Session, at int64, timeout bool) {
+	s.NextAt, s.NextTimeout = at, timeout
+	sh := sm.shardFor(s.UserID)
+	sh.mu.Lock()
+	s.NextSeq = sh.schedule.schedule(s, at, timeout)
+	sh.mu.Unlock()
+}
+
+// holds : s 가 아직 SessionManager 에 남아 있는 세션인지 (삭제되지 않았는지)
+func (sm *SessionManager) holds(s *Session) bool {
+	sh := sm.shardFor(s.UserID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.sessions[s.ID] == s
+}
+
+// shardFor : userID 의 세션이 들어 있는 조각
+func (sm *SessionManager) shardFor(userID string) *sessionShard {
+	return sm.shards[shardIndex(userID, len(sm.shards))]
+}
+
+// emit : 이벤트를 큐로 보내고 생성 수를 셉니다.
+func (sm *SessionManager) emit(ev *event.Event) {
+	sm.events.Push(ev)
+	sm.generated.Add(1)
+}
+
+// =======================
+// Session lifecycle
+// =======================
+
+// sessionStartEvent : 세션의 첫 행동 이벤트 first 와 같은 시각의 session_start
+// 기기 / 유입 경로 / 유저 프로필은 Populate 가 채운 first 의 값을 그대로 사용합니다.
+// 상태와 진입 화면 (entryPage) 은 첫 전이 이전, 세션이 시작된 상태의 값입니다.
+func (sm *SessionManager) sessionStartEvent(s *Session, first *event.Event, entryPage string) *event.Event {
+	ev := sm.lifecycleEvent(s, event.TypeSessionStart, first.EventTs)
+	ev.Attributes.State = first.Attributes.PrevState
+	ev.Attributes.Device = first.Attributes.Device
+	ev.Attributes.Referrer = first.Attributes.Referrer
+	ev.Attributes.User = first.Attributes.User
+	ev.Attributes.Session = &event.SessionInfo{EntryPage: entryPage}
+
+	if sm.metrics != nil {
+		sm.metrics.IncSessionStart()
+		if s.Persona != nil {
+			sm.metrics.IncPersonaSession(s.Persona.Name)
 		}
-		sm.mu.Unlock()
 	}
+	return ev
 }
 
-// ID 생성
-func generateSessionID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+// endSession : at 시각에 session_end 를 내보내고 세션을 삭제합니다.
+func (sm *SessionManager) endSession(s *Session, at int64, reason string) {
+	ev := sm.lifecycleEvent(s, event.TypeSessionEnd, at)
+	ev.Attributes.Device = s.Device
+	ev.Attributes.Referrer = s.GetReferrer()
+	ev.Attributes.User = s.UserInfo()
+	ev.Attributes.Session = &event.SessionInfo{
+		EndReason:  reason,
+		DurationMs: at - s.StartedAt,
+		EventCount: s.EventCount,
+	}
+
+	sm.deleteSession(s.UserID, s.ID)
+	sm.emit(ev)
+	if sm.metrics != nil {
+		sm.metrics.IncSessionEnd(reason)
+	}
+}
+
+// endReason : 종료 상태에 도달한 세션의 종료 사유
+func (sm *SessionManager) endReason(s *Session) string {
+	if s.Purchased {
+		return event.EndReasonPurchaseExit
+	}
+	return event.EndReasonExit
+}
+
+func (sm *SessionManager) lifecycleEvent(s *Session, evType string, at int64) *event.Event {
+	return &event.Event{
+		EventID:   fmt.Sprintf("evt-%d-%09d", at, s.Rand().Int64N(1_000_000_000)),
+		EventType: evType,
+		EventTs:   at,
+		UserID:    s.GetUserID(),
+		SessionID: s.GetID(),
+		Attributes: event.EventAttributes{
+			State: string(s.GetState()),
+		},
+		SchemaVersion: event.SchemaVersion,
+	}
 }
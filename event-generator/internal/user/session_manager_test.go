@@ -0,0 +1,345 @@
+package user_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"event-generator/internal/clock"
+	"event-generator/internal/event"
+	"event-generator/internal/fsm"
+	"event-generator/internal/generator"
+	"event-generator/internal/metrics"
+	"event-generator/internal/rng"
+	"event-generator/internal/user"
+)
+
+// TestSessionManagerConcurrentStep : 많은 고루틴이 작은 유저 풀을 두고 Step 을 동시에 호출해도
+// 세션마다 이벤트 흐름이 한 고루틴이 차례로 만든 것과 같은지 검사합니다. 경합 검사기와 함께 실행합니다.
+//
+//	go test -race -run TestSessionManagerConcurrentStep ./internal/user/
+//
+// handoff 하위 테스트는 중간에 생성을 멈추고 세션을 SessionStore 에 저장한 뒤 새 SessionManager 로 복원해 이어서 진행하며,
+// 복원 전후의 이벤트도 같은 검사를 통과해야 합니다.
+//
+// 검사 항목
+//   - session_start 로 시작하고 session_end 뒤에는 이벤트가 없음
+//   - 세션 안의 event_ts 가 줄어들지 않음
+//   - 행동 이벤트의 prev_state 가 직전 행동 이벤트의 state 와 같고, 모델에 있는 전이임
+//   - session_end 의 event_count / state 가 실제 행동 이벤트 수 / 마지막 상태와 같음
+//   - 한 유저의 세션이 겹치지 않음 (이전 세션의 session_end 가 다음 session_start 보다 늦지 않음)
+func TestSessionManagerConcurrentStep(t *testing.T) {
+	const (
+		goroutines = 32
+		users      = 1000 // 작은 풀이라 고루틴들이 같은 유저를 두고 경합
+		shards     = 4
+		duration   = time.Second
+		ttl        = 2 * time.Minute // 짧은 TTL 로 만료 경로도 함께 검사
+	)
+
+	setup := func() (*recorder, *fsm.Model, func() *user.SessionManager) {
+		clk := clock.NewScaled(time.Now(), 1000)
+		catalog := generator.DefaultCatalog()
+		up := user.NewUserPool(nil, user.DefaultProfileDistribution(), catalog.CountryNames(), user.DefaultPersonas(), clk)
+		up.EnsureUsers(users)
+
+		rec := newRecorder()
+		model := fsm.DefaultModel()
+		return rec, model, func() *user.SessionManager {
+			return user.NewSessionManager(up, fsm.NewSimpleFSM(model), generator.NewPayloadGenerator(catalog), rec, metrics.NewInMemory(), ttl, shards, clk, rng.NewFactory(0))
+		}
+	}
+
+	check := func(t *testing.T, rec *recorder, model *fsm.Model, steps int64) {
+		t.Helper()
+		if rec.n == 0 {
+			t.Fatalf("no events after %d steps", steps)
+		}
+		violations := verify(rec, model)
+		for i, v := range violations {
+			if i == 20 {
+				t.Errorf("... and %d more", len(violations)-i)
+				break
+			}
+			t.Error(v)
+		}
+	}
+
+	t.Run("continuous", func(t *testing.T) {
+		rec, model, newManager := setup()
+		steps := runSteps(newManager(), goroutines, duration)
+		check(t, rec, model, steps)
+	})
+
+	handoff := func(t *testing.T, store user.SessionStore) {
+		rec, model, newManager := setup()
+		sm := newManager()
+		steps := runSteps(sm, goroutines, duration/2)
+
+		saved, err := sm.Save(store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sm = newManager()
+		restored, skipped, err := sm.Restore(store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if restored != saved || skipped != 0 {
+			t.Fatalf("handoff saved %d sessions, restored %d (skipped %d)", saved, restored, skipped)
+		}
+		if got := sm.ActiveSessions(); got != restored {
+			t.Fatalf("%d active sessions after restoring %d", got, restored)
+		}
+
+		steps += runSteps(sm, goroutines, duration/2)
+		check(t, rec, model, steps)
+	}
+	t.Run("handoff/memory", func(t *testing.T) {
+		handoff(t, user.NewMemoryStore())
+	})
+	t.Run("handoff/bolt", func(t *testing.T) {
+		store, err := user.OpenBoltStore(filepath.Join(t.TempDir(), "sessions.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		handoff(t, store)
+	})
+}
+
+// TestSessionTimeout : 다음 행동 없이 방치한 세션이 마지막 행동 + TTL 시각에 session_end(timeout) 로 끝나고,
+// 세션 길이 / 행동 수가 맞는지 확인합니다.
+func TestSessionTimeout(t *testing.T) {
+	const ttl = 10 * time.Second
+
+	clk := clock.NewVirtual(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	rngs := rng.NewFactory(1)
+	catalog := generator.DefaultCatalog()
+	// 모든 세션이 첫 행동 뒤 방치됨
+	personas := []user.Persona{{Name: "idle", Weight: 1, DwellScale: 1, Abandon: 1}}
+	up := user.NewUserPool(rngs.Source("user_pool"), user.DefaultProfileDistribution(), catalog.CountryNames(), personas, clk)
+	up.EnsureUsers(50)
+
+	rec := newRecorder()
+	sm := user.NewSessionManager(up, fsm.NewSimpleFSM(nil), generator.NewPayloadGenerator(catalog), rec, metrics.NewInMemory(), ttl, 1, clk, rngs)
+	for range 2000 {
+		clk.Advance(100 * time.Millisecond)
+		sm.Step()
+	}
+
+	var timeouts int
+	for sid, evs := range rec.sessions {
+		end := evs[len(evs)-1]
+		if end.EventType != event.TypeSessionEnd {
+			continue
+		}
+		info := end.Attributes.Session
+		if info.EndReason == event.EndReasonExit || info.EndReason == event.EndReasonPurchaseExit {
+			continue // 첫 행동이 곧바로 종료 상태인 세션
+		}
+		if info.EndReason != event.EndReasonTimeout {
+			t.Errorf("%s: end_reason %q, want %q", sid, info.EndReason, event.EndReasonTimeout)
+			continue
+		}
+		timeouts++
+
+		start, last := evs[0], evs[len(evs)-2]
+		if want := last.EventTs + ttl.Milliseconds(); end.EventTs != want {
+			t.Errorf("%s: timeout at %d, want last action %d + ttl = %d", sid, end.EventTs, last.EventTs, want)
+		}
+		if want := end.EventTs - start.EventTs; info.DurationMs != want {
+			t.Errorf("%s: duration_ms %d, want %d", sid, info.DurationMs, want)
+		}
+		if want := len(evs) - 2; info.EventCount != want {
+			t.Errorf("%s: event_count %d, want %d", sid, info.EventCount, want)
+		}
+	}
+	if timeouts == 0 {
+		t.Fatal("no session ended with a timeout")
+	}
+}
+
+// runSteps : g 개의 고루틴으로 d 동안 Step 을 반복하고, 진행 중에 상태 조회도 함께 호출합니다.
+func runSteps(sm *user.SessionManager, g int, d time.Duration) int64 {
+	var (
+		steps atomic.Int64
+		stop  atomic.Bool
+		wg    sync.WaitGroup
+	)
+	for range g {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				sm.Step()
+				steps.Add(1)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for !stop.Load() {
+			sm.ActiveSessions()
+			sm.ScheduleLag()
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	time.Sleep(d)
+	stop.Store(true)
+	wg.Wait()
+	return steps.Load()
+}
+
+// recorder : 세션별로 이벤트를 받은 순서대로 모으는 큐
+// 세션 소유 고루틴이 순서대로 Push 하므로 세션 안의 순서는 생성 순서와 같습니다.
+type recorder struct {
+	mu       sync.Mutex
+	sessions map[string][]*event.Event
+	n        int
+}
+
+func newRecorder() *recorder {
+	return &recorder{sessions: make(map[string][]*event.Event)}
+}
+
+func (r *recorder) Push(ev *event.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[ev.SessionID] = append(r.sessions[ev.SessionID], ev)
+	r.n++
+}
+
+// span : 한 세션의 시작 / 종료 시각 (종료되지 않았으면 ended=false)
+type span struct {
+	id         string
+	start, end int64
+	ended      bool
+}
+
+// verify : 기록한 이벤트에서 세션 흐름 / 소유 규칙 위반을 찾습니다.
+func verify(rec *recorder, model *fsm.Model) []string {
+	var out []string
+	byUser := make(map[string][]span)
+
+	for sid, evs := range rec.sessions {
+		fail := func(format string, args ...any) {
+			out = append(out, fmt.Sprintf("%s: ", sid)+fmt.Sprintf(format, args...))
+		}
+		if evs[0].EventType != event.TypeSessionStart {
+			fail("first event is %s, want %s", evs[0].EventType, event.TypeSessionStart)
+			continue
+		}
+
+		sp := span{id: sid, start: evs[0].EventTs}
+		var (
+			count int
+			state = model.Initial
+			last  = evs[0].EventTs
+		)
+		for i, ev := range evs[1:] {
+			if ev.EventTs < last {
+				fail("event_ts went backwards at #%d (%d < %d)", i+1, ev.EventTs, last)
+			}
+			last = ev.EventTs
+
+			switch ev.EventType {
+			case event.TypeSessionStart:
+				fail("duplicate %s at #%d", event.TypeSessionStart, i+1)
+			case event.TypeSessionEnd:
+				if i != len(evs)-2 {
+					fail("%d events after %s", len(evs)-2-i, event.TypeSessionEnd)
+				}
+				info := ev.Attributes.Session
+				if info == nil || info.EventCount != count {
+					fail("session_end event_count does not match %d behavior events", count)
+				}
+				if ev.Attributes.State != string(state) {
+					fail("session_end state %s, want %s", ev.Attributes.State, state)
+				}
+				sp.end, sp.ended = ev.EventTs, true
+			default:
+				count++
+				if ev.Attributes.PrevState != string(state) {
+					fail("#%d %s: prev_state %s, want %s (interleaved steps)", i+1, ev.EventType, ev.Attributes.PrevState, state)
+				} else if !allowed(model, state, ev) {
+					fail("#%d impossible transition %s -(%s)-> %s", i+1, state, ev.EventType, ev.Attributes.State)
+				}
+				state = fsm.State(ev.Attributes.State)
+			}
+		}
+		byUser[evs[0].UserID] = append(byUser[evs[0].UserID], sp)
+	}
+
+	// 한 유저의 세션은 겹치지 않아야 함
+	for uid, spans := range byUser {
+		sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+		for i := 1; i < len(spans); i++ {
+			prev := spans[i-1]
+			if !prev.ended || prev.end > spans[i].start {
+				out = append(out, fmt.Sprintf("user %s: session %s started while %s was still active (ended=%v end=%d start=%d)", uid, spans[i].id, prev.id, prev.ended, prev.end, spans[i].start))
+			}
+		}
+	}
+	return out
+}
+
+// allowed : from 상태에서 ev 로의 전이가 모델에 있는지 (back 은 도착 상태가 세션 이력에 따라 달라서 이벤트만 확인)
+func allowed(model *fsm.Model, from fsm.State, ev *event.Event) bool {
+	for _, tr := range model.Transitions[from] {
+		if string(tr.Event) != ev.EventType {
+			continue
+		}
+		if tr.Event == fsm.EventBack || string(tr.NextState) == ev.Attributes.State {
+			return true
+		}
+	}
+	return false
+}
+
+// discard : 이벤트 수만 세고 버리는 큐
+type discard struct {
+	n atomic.Int64
+}
+
+func (q *discard) Push(ev *event.Event) {
+	q.n.Add(1)
+}
+
+// BenchmarkStep : SessionManager.Step 처리량이 세션 저장소 조각 수와 Step 고루틴 수에 따라 어떻게 늘어나는지 측정합니다.
+// 이벤트는 버리고 (Sink / 직렬화 제외) Step 만 반복 호출합니다. 고루틴 수는 -cpu 로 바꿉니다.
+//
+//	go test -run '^$' -bench BenchmarkStep -cpu 1,4,12 ./internal/user/
+//
+// 시계는 실제 시간보다 1000 배 빠르게 흐르므로 체류 시간이 지난 세션의 다음 행동 / 만료까지 함께 측정됩니다.
+func BenchmarkStep(b *testing.B) {
+	const users = 100000
+
+	for _, shards := range []int{1, 64} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			clk := clock.NewScaled(time.Now(), 1000)
+			catalog := generator.DefaultCatalog()
+			up := user.NewUserPool(nil, user.DefaultProfileDistribution(), catalog.CountryNames(), user.DefaultPersonas(), clk)
+			up.EnsureUsers(users)
+
+			var q discard
+			sm := user.NewSessionManager(up, fsm.NewSimpleFSM(nil), generator.NewPayloadGenerator(catalog), &q, metrics.NewInMemory(), 30*time.Minute, shards, clk, rng.NewFactory(0))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					sm.Step()
+				}
+			})
+			b.ReportMetric(float64(q.n.Load())/float64(b.N), "events/op")
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package user
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// =======================
+// Session shard
+// =======================
+
+// sessionShard : userID 해시로 나눈 세션 저장소 조각
+// 유저의 세션은 항상 같은 조각에 있으므로 세션 생성 / 예약 / 삭제는 그 조각의 락만 잡습니다.
+// 만료는 조각마다 스케줄러에 예약된 timeout 항목으로 처리하므로 전체 맵을 훑지 않습니다.
+type sessionShard struct {
+	mu            sync.Mutex
+	sessions      map[string]*Session // key: sessionID
+	userToSession map[string]string   // key: userID, value: sessionID
+	schedule      scheduler           // 활성 세션의 다음 행동 시각 (think-time 스케줄)
+}
+
+func newSessionShard() *sessionShard {
+	return &sessionShard{
+		sessions:      make(map[string]*Session),
+		userToSession: make(map[string]string),
+	}
+}
+
+// popDue : now 까지 예약된 세션 중 가장 이른 항목 (이미 삭제된 세션의 예약은 건너뜀)
+func (sh *sessionShard) popDue(now int64) (scheduled, bool) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	for {
+		it, ok := sh.schedule.popDue(now)
+		if !ok {
+			return scheduled{}, false
+		}
+		if sh.sessions[it.session.ID] == it.session {
+			return it, true
+		}
+	}
+}
+
+// shardIndex : userID 의 조각 번호
+// 시드 모드에서 같은 유저가 항상 같은 조각에 들어가도록 프로세스마다 달라지는 maphash 대신 FNV 를 사용합니다.
+func shardIndex(userID string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(userID))
+	return int(h.Sum32() % uint32(n))
+}
@@ -0,0 +1,281 @@
+package user
+
+import (
+	"event-generator/internal/fsm"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+)
+
+// =======================
+// SessionStore Interface
+// =======================
+
+// SessionStore 는 진행 중인 세션을 SessionManager 밖에 보관하는 저장소입니다.
+// SessionManager.Save 로 저장했다가 Restore 로 불러오면 상태 / 장바구니 / 난수 스트림 / 다음 행동 예약까지 그대로 이어집니다.
+//
+// 저장소는 세션의 복사본을 보관하므로 Put 이후 원본을 바꿔도 저장된 값은 바뀌지 않으며,
+// Get / Range / Expire 가 돌려주는 세션도 새로 만든 값입니다. (Profile / Persona 는 비어 있어 SessionManager 가 다시 연결)
+type SessionStore interface {
+	Get(sessionID string) (*Session, bool, error)
+	// Put : 세션을 저장합니다. 같은 ID 가 있으면 덮어씁니다. (여러 개면 한 번에 기록)
+	Put(sessions ...*Session) error
+	Delete(sessionIDs ...string) error
+	// Expire : ExpiresAt 가 now 이하인 세션을 ID 순으로 fn 에 넘긴 뒤 삭제합니다.
+	// fn 이 오류를 반환하면 중단하며 아무것도 삭제하지 않습니다. fn 안에서 저장소를 호출하면 안 됩니다.
+	Expire(now int64, fn func(*Session) error) error
+	// Range : 모든 세션을 ID 순으로 fn 에 넘깁니다. fn 이 오류를 반환하면 중단합니다.
+	Range(fn func(*Session) error) error
+	Count() (int, error)
+	Close() error
+}
+
+// =======================
+// Session record
+// =======================
+
+// sessionRecordVersion : 저장 형식 버전 (필드 의미가 바뀌면 올림)
+const sessionRecordVersion = 1
+
+// sessionRecord : 저장소에 기록하는 세션 형식
+// Profile / Persona 는 유저 풀이 가지고 있으므로 저장하지 않고 복원할 때 UserID 로 다시 연결합니다.
+type sessionRecord struct {
+	Version                 int            `json:"version"`
+	ID                      string         `json:"id"`
+	UserID                  string         `json:"user_id"`
+	State                   fsm.State      `json:"state"`
+	PrevState               fsm.State      `json:"prev_state,omitempty"`
+	BackState               fsm.State      `json:"back_state,omitempty"`
+	PageType                string         `json:"page_type,omitempty"`
+	EventPage               string         `json:"event_page,omitempty"`
+	BrowsingCountryCategory string         `json:"browsing_country_category,omitempty"`
+	BrowsingProductCategory string         `json:"browsing_product_category,omitempty"`
+	SearchKeyword           string         `json:"search_keyword,omitempty"`
+	PageIndex               int            `json:"page_index,omitempty"`
+	LastEventTs             int64          `json:"last_event_ts"`
+	ExpiresAt               int64          `json:"expires_at"`
+	LastPicked              string         `json:"last_picked,omitempty"`
+	LastProductID           string         `json:"last_product_id,omitempty"`
+	LastCategory            string         `json:"last_category,omitempty"`
+	LastCountry             string         `json:"last_country,omitempty"`
+	LastQuantity            int            `json:"last_quantity,omitempty"`
+	LastVendor              string         `json:"last_vendor,omitempty"`
+	Cart                    []fsm.CartLine `json:"cart,omitempty"`
+	LastOrder               []fsm.CartLine `json:"last_order,omitempty"`
+	Device                  string         `json:"device,omitempty"`
+	Referrer                string         `json:"referrer,omitempty"`
+	SessionNumber           int            `json:"session_number,omitempty"`
+	StartedAt               int64          `json:"started_at"`
+	EventCount              int            `json:"event_count,omitempty"`
+	Purchased               bool           `json:"purchased,omitempty"`
+	NextAt                  int64          `json:"next_at"`
+	NextTimeout             bool           `json:"next_timeout,omitempty"`
+	NextSeq                 uint64         `json:"next_seq,omitempty"`
+	RNG                     []byte         `json:"rng"` // rand.PCG 상태
+}
+
+func newSessionRecord(s *Session) (sessionRecord, error) {
+	state, err := s.src.MarshalBinary()
+	if err != nil {
+		return sessionRecord{}, fmt.Errorf("session %s: encode rng: %w", s.ID, err)
+	}
+	return sessionRecord{
+		Version:                 sessionRecordVersion,
+		ID:                      s.ID,
+		UserID:                  s.UserID,
+		State:                   s.State,
+		PrevState:               s.PrevState,
+		BackState:               s.BackState,
+		PageType:                s.PageType,
+		EventPage:               s.EventPage,
+		BrowsingCountryCategory: s.BrowsingCountryCategory,
+		BrowsingProductCategory: s.BrowsingProductCategory,
+		SearchKeyword:           s.SearchKeyword,
+		PageIndex:               s.PageIndex,
+		LastEventTs:             s.LastEventTs,
+		ExpiresAt:               s.ExpiresAt,
+		LastPicked:              s.LastPicked,
+		LastProductID:           s.LastProductID,
+		LastCategory:            s.LastCategory,
+		LastCountry:             s.LastCountry,
+		LastQuantity:            s.LastQuantity,
+		LastVendor:              s.LastVendor,
+		Cart:                    append([]fsm.CartLine(nil), s.Cart...),
+		LastOrder:               append([]fsm.CartLine(nil), s.LastOrder...),
+		Device:                  s.Device,
+		Referrer:                s.Referrer,
+		SessionNumber:           s.SessionNumber,
+		StartedAt:               s.StartedAt,
+		EventCount:              s.EventCount,
+		Purchased:               s.Purchased,
+		NextAt:                  s.NextAt,
+		NextTimeout:             s.NextTimeout,
+		NextSeq:                 s.NextSeq,
+		RNG:                     state,
+	}, nil
+}
+
+func (r sessionRecord) session() (*Session, error) {
+	if r.Version != sessionRecordVersion {
+		return nil, fmt.Errorf("session %s: unsupported record version %d (want %d)", r.ID, r.Version, sessionRecordVersion)
+	}
+	src := &rand.PCG{}
+	if err := src.UnmarshalBinary(r.RNG); err != nil {
+		return nil, fmt.Errorf("session %s: decode rng: %w", r.ID, err)
+	}
+	return &Session{
+		ID:                      r.ID,
+		UserID:                  r.UserID,
+		State:                   r.State,
+		PrevState:               r.PrevState,
+		BackState:               r.BackState,
+		PageType:                r.PageType,
+		EventPage:               r.EventPage,
+		BrowsingCountryCategory: r.BrowsingCountryCategory,
+		BrowsingProductCategory: r.BrowsingProductCategory,
+		SearchKeyword:           r.SearchKeyword,
+		PageIndex:               r.PageIndex,
+		LastEventTs:             r.LastEventTs,
+		ExpiresAt:               r.ExpiresAt,
+		LastPicked:              r.LastPicked,
+		LastProductID:           r.LastProductID,
+		LastCategory:            r.LastCategory,
+		LastCountry:             r.LastCountry,
+		LastQuantity:            r.LastQuantity,
+		LastVendor:              r.LastVendor,
+		Cart:                    append([]fsm.CartLine(nil), r.Cart...),
+		LastOrder:               append([]fsm.CartLine(nil), r.LastOrder...),
+		Device:                  r.Device,
+		Referrer:                r.Referrer,
+		SessionNumber:           r.SessionNumber,
+		StartedAt:               r.StartedAt,
+		EventCount:              r.EventCount,
+		Purchased:               r.Purchased,
+		NextAt:                  r.NextAt,
+		NextTimeout:             r.NextTimeout,
+		NextSeq:                 r.NextSeq,
+		src:                     src,
+		rng:                     rand.New(src),
+	}, nil
+}
+
+// snapshot : 저장 형식을 거쳐 만든 세션 복사본 (소유 락을 잡은 상태에서 호출)
+func (s *Session) snapshot() (*Session, error) {
+	r, err := newSessionRecord(s)
+	if err != nil {
+		return nil, err
+	}
+	return r.session()
+}
+
+// =======================
+// MemoryStore
+// =======================
+
+// MemoryStore : 프로세스 안에서만 유지되는 SessionStore (SessionManager 사이에 세션을 넘기거나 검증할 때 사용)
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[string]sessionRecord
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]sessionRecord)}
+}
+
+func (m *MemoryStore) Get(sessionID string) (*Session, bool, error) {
+	m.mu.RLock()
+	r, ok := m.records[sessionID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	s, err := r.session()
+	return s, err == nil, err
+}
+
+func (m *MemoryStore) Put(sessions ...*Session) error {
+	records := make([]sessionRecord, 0, len(sessions))
+	for _, s := range sessions {
+		r, err := newSessionRecord(s)
+		if err != nil {
+			return err
+		}
+		records = append(records, r)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range records {
+		m.records[r.ID] = r
+	}
+	return nil
+}
+
+func (m *MemoryStore) Delete(sessionIDs ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range sessionIDs {
+		delete(m.records, id)
+	}
+	return nil
+}
+
+func (m *MemoryStore) Expire(now int64, fn func(*Session) error) error {
+	var expired []string
+	err := m.each(func(r sessionRecord) error {
+		if r.ExpiresAt > now {
+			return nil
+		}
+		s, err := r.session()
+		if err != nil {
+			return err
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+		expired = append(expired, r.ID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return m.Delete(expired...)
+}
+
+func (m *MemoryStore) Range(fn func(*Session) error) error {
+	return m.each(func(r sessionRecord) error {
+		s, err := r.session()
+		if err != nil {
+			return err
+		}
+		return fn(s)
+	})
+}
+
+// each : 기록을 ID 순으로 fn 에 넘깁니다. (fn 은 락 밖에서 호출)
+func (m *MemoryStore) each(fn func(sessionRecord) error) error {
+	m.mu.RLock()
+	records := make([]sessionRecord, 0, len(m.records))
+	for _, r := range m.records {
+		records = append(records, r)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	for _, r := range records {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) Count() (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.records), nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
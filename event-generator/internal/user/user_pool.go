@@ -1,24 +1,77 @@
 package user
 
 import (
+	"encoding/json"
+	"errors"
+	"event-generator/internal/clock"
 	"fmt"
-	"math/rand/v2" // v1 대신 v2를 사용합니다.
+	"math/rand/v2"
+	"os"
+	"path/filepath"
 	"sync"
 )
 
+// 접속 기기
+const (
+	DeviceIOS     = "ios"
+	DeviceAndroid = "android"
+	DeviceWeb     = "web"
+)
+
+// User : 실행이 바뀌어도 유지되는 유저 프로필
+// 프로필 속성은 생성 후 바뀌지 않고, 방문 기록(SessionCount / LastSeen)만 UserPool 이 갱신합니다.
 type User struct {
-	ID string
+	ID                    string   `json:"id"`
+	HomeCountry           string   `json:"home_country"`
+	Language              string   `json:"language"`
+	Device                string   `json:"device"` // 유저마다 고정 (세션이 바뀌어도 유지)
+	SignupDate            string   `json:"signup_date"`
+	LoyaltyTier           string   `json:"loyalty_tier"`
+	PriceSensitivity      float64  `json:"price_sensitivity"`
+	PreferredDestinations []string `json:"preferred_destinations,omitempty"`
+	Persona               string   `json:"persona"` // 행동 유형 (설정의 personas 이름)
+
+	// 방문 기록 (재방문 / 코호트 분석용)
+	SessionCount int   `json:"session_count"`
+	LastSeen     int64 `json:"last_seen,omitempty"` // 마지막 세션 시작 시각 (epoch millis)
 }
 
 type UserPool struct {
 	mu    sync.RWMutex
 	users []*User
+	byID  map[string]*User
+	limit int // 0 이 아니면 앞의 limit 명 중에서만 유저를 뽑음 (SetLimit)
+
+	dist         ProfileDistribution
+	destinations []string // 선호 여행지 후보 (카탈로그 국가)
+	personas     *personaSet
+	clock        clock.Clock
+
+	// seed 모드에서만 사용하는 전용 난수 스트림 (nil 이면 전역 rand 사용)
+	src   *rand.PCG // rng 의 소스 (체크포인트 저장용)
+	rng   *rand.Rand
+	rngMu sync.Mutex
 }
 
-func NewUserPool() *UserPool {
-	return &UserPool{
-		users: make([]*User, 0),
+// NewUserPool
+// src 가 nil 이면 thread-safe 한 전역 rand 를 사용하고,
+// nil 이 아니면 재현 가능한 유저 선택을 위해 src 의 난수 스트림을 뮤텍스로 보호하며 사용합니다.
+// 새 유저 프로필은 dist 분포로 만들고 personas 의 Weight 비율대로 페르소나를 배정하며,
+// 가입일은 clk 기준으로 계산합니다.
+func NewUserPool(src *rand.PCG, dist ProfileDistribution, destinations []string, personas []Persona, clk clock.Clock) *UserPool {
+	up := &UserPool{
+		users:        make([]*User, 0),
+		byID:         make(map[string]*User),
+		dist:         dist,
+		destinations: destinations,
+		personas:     newPersonaSet(personas),
+		clock:        clk,
+		src:          src,
 	}
+	if src != nil {
+		up.rng = rand.New(src)
+	}
+	return up
 }
 
 // LoadController가 필요한 유저 수만큼 확보
@@ -26,18 +79,19 @@ func (up *UserPool) EnsureUsers(required int) {
 	up.mu.Lock()
 	defer up.mu.Unlock()
 
-	current := len(up.users)
-	if current >= required {
-		return
-	}
-
-	needed := required - current
-
-	for i := 0; i < needed; i++ {
-		newUser := &User{
-			ID: fmt.Sprintf("user_%d", current+i+1),
+	now := up.clock.Now()
+	next := len(up.users) + 1
+	for len(up.users) < required {
+		id := fmt.Sprintf("user_%d", next)
+		next++
+		if _, exists := up.byID[id]; exists {
+			// 파일에서 불러온 유저와 ID 가 겹치면 건너뜀
+			continue
 		}
-		up.users = append(up.users, newUser)
+		u := up.dist.newProfile(id, now, up.destinations, up.intN, up.float)
+		up.assignPersona(u)
+		up.users = append(up.users, u)
+		up.byID[id] = u
 	}
 }
 
@@ -47,13 +101,102 @@ func (up *UserPool) GetRandomUser() *User {
 	defer up.mu.RUnlock()
 
 	n := len(up.users)
+	if up.limit > 0 {
+		n = min(n, up.limit)
+	}
 	if n == 0 {
 		return nil
 	}
 
-	// [수정] rand.Intn -> rand.IntN (v2 전역 함수 사용하여 Thread-safe 확보)
-	idx := rand.IntN(n)
-	return up.users[idx]
+	return up.users[up.intN(n)]
+}
+
+// SetLimit : 새 세션을 시작할 유저를 앞의 n 명으로 제한합니다. (0 이면 제한 없음)
+// 나머지 유저는 지우지 않으므로 프로필 저장 / 재방문 기록은 그대로 유지됩니다.
+func (up *UserPool) SetLimit(n int) {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	up.limit = n
+}
+
+// ActiveCount : 새 세션을 시작할 수 있는 유저 수 (SetLimit 적용)
+func (up *UserPool) ActiveCount() int {
+	up.mu.RLock()
+	defer up.mu.RUnlock()
+	if up.limit > 0 {
+		return min(len(up.users), up.limit)
+	}
+	return len(up.users)
+}
+
+// Get : ID 로 유저 조회
+func (up *UserPool) Get(id string) (*User, bool) {
+	up.mu.RLock()
+	defer up.mu.RUnlock()
+	u, ok := up.byID[id]
+	return u, ok
+}
+
+// Persona : 유저에게 배정된 페르소나 (없으면 nil)
+func (up *UserPool) Persona(u *User) *Persona {
+	return up.personas.byName[u.Persona]
+}
+
+// assignPersona : 설정 비율대로 페르소나를 배정합니다.
+func (up *UserPool) assignPersona(u *User) {
+	if p := up.personas.pick(up.float()); p != nil {
+		u.Persona = p.Name
+	}
+}
+
+// RecordVisit : 새 세션 시작을 기록하고 이 세션이 유저의 몇 번째 세션인지 반환합니다.
+func (up *UserPool) RecordVisit(u *User, now int64) int {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	u.SessionCount++
+	u.LastSeen = now
+	return u.SessionCount
+}
+
+func (up *UserPool) intN(n int) int {
+	if up.rng == nil {
+		return rand.IntN(n)
+	}
+	up.rngMu.Lock()
+	defer up.rngMu.Unlock()
+	return up.rng.IntN(n)
+}
+
+func (up *UserPool) float() float64 {
+	if up.rng == nil {
+		return rand.Float64()
+	}
+	up.rngMu.Lock()
+	defer up.rngMu.Unlock()
+	return up.rng.Float64()
+}
+
+// RandState : 전용 난수 스트림의 현재 상태 (전역 rand 를 사용하면 nil)
+func (up *UserPool) RandState() ([]byte, error) {
+	if up.src == nil {
+		return nil, nil
+	}
+	up.rngMu.Lock()
+	defer up.rngMu.Unlock()
+	return up.src.MarshalBinary()
+}
+
+// SetRandState : RandState 로 저장한 상태부터 난수 스트림을 이어갑니다. (전역 rand 를 사용하면 무시)
+func (up *UserPool) SetRandState(state []byte) error {
+	if up.src == nil || state == nil {
+		return nil
+	}
+	up.rngMu.Lock()
+	defer up.rngMu.Unlock()
+	if err := up.src.UnmarshalBinary(state); err != nil {
+		return fmt.Errorf("user pool rng: %w", err)
+	}
+	return nil
 }
 
 func (up *UserPool) TotalCount() int {
@@ -61,3 +204,84 @@ func (up *UserPool) TotalCount() int {
 	defer up.mu.RUnlock()
 	return len(up.users)
 }
+
+// =======================
+// Persistence
+// =======================
+
+// profileFileVersion : 프로필 파일 형식 버전
+const profileFileVersion = 1
+
+type profileFile struct {
+	Version int     `json:"version"`
+	Users   []*User `json:"users"`
+}
+
+// Load : 저장된 프로필 파일을 읽어 유저를 복원합니다. 파일이 없으면 아무것도 하지 않습니다.
+// 반환값은 불러온 유저 수입니다.
+func (up *UserPool) Load(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read user profiles %s: %w", path, err)
+	}
+
+	var f profileFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return 0, fmt.Errorf("parse user profiles %s: %w", path, err)
+	}
+	if f.Version != profileFileVersion {
+		return 0, fmt.Errorf("user profiles %s: unsupported version %d (want %d)", path, f.Version, profileFileVersion)
+	}
+
+	up.mu.Lock()
+	defer up.mu.Unlock()
+
+	for i, u := range f.Users {
+		if u == nil || u.ID == "" {
+			return 0, fmt.Errorf("user profiles %s: users[%d] has no id", path, i)
+		}
+		if _, dup := up.byID[u.ID]; dup {
+			return 0, fmt.Errorf("user profiles %s: duplicate user id %q", path, u.ID)
+		}
+		// 페르소나 설정이 바뀌어 이름을 찾을 수 없으면 다시 배정
+		if _, ok := up.personas.byName[u.Persona]; !ok {
+			up.assignPersona(u)
+		}
+		up.users = append(up.users, u)
+		up.byID[u.ID] = u
+	}
+	return len(f.Users), nil
+}
+
+// Save : 전체 유저 프로필을 파일에 저장합니다.
+// 임시 파일에 쓴 뒤 rename 하므로 저장 도중 종료되어도 기존 파일이 깨지지 않습니다.
+func (up *UserPool) Save(path string) error {
+	up.mu.RLock()
+	data, err := json.MarshalIndent(profileFile{Version: profileFileVersion, Users: up.users}, "", "  ")
+	up.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("encode user profiles: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("save user profiles %s: %w", path, err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("save user profiles %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("save user profiles %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("save user profiles %s: %w", path, err)
+	}
+	return nil
+}
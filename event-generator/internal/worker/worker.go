@@ -2,51 +2,47 @@ package worker
 
 import (
 	"context"
-	"encoding/json"
-	"time"
 
 	"event-generator/internal/event"
 	"event-generator/internal/metrics"
-
-	"github.com/segmentio/kafka-go"
+	"event-generator/internal/sink"
 )
 
+// 한 번의 Sink.Write 로 묶어 보낼 최대 이벤트 수
+const maxBatch = 500
+
 type Worker struct {
-	id        int
-	eventCh   <-chan *event.Event
-	metrics   metrics.Metrics
-	kafkaAddr string
-	topic     string
+	id      int
+	eventCh <-chan *event.Event
+	metrics metrics.Metrics
+	sink    sink.Sink
+
+	// Sink 가 전송 확정을 직접 기록하지 않으면 Write 성공을 전송 완료로 기록
+	recordDelivery bool
 }
 
 func NewWorker(
 	id int,
 	eventCh <-chan *event.Event,
 	m metrics.Metrics,
-	kafkaAddr, topic string,
+	s sink.Sink,
 ) *Worker {
+	reporter, ok := s.(sink.DeliveryReporter)
 	return &Worker{
-		id:        id,
-		eventCh:   eventCh,
-		metrics:   m,
-		kafkaAddr: kafkaAddr,
-		topic:     topic,
+		id:             id,
+		eventCh:        eventCh,
+		metrics:        m,
+		sink:           s,
+		recordDelivery: !ok || !reporter.ReportsDelivery(),
 	}
 }
 
+// Run : 채널에서 이벤트를 꺼내 Sink 로 전송합니다.
+// 채널이 닫히면 남은 이벤트를 모두 보내고 Sink 를 Flush 한 뒤 반환합니다.
+// ctx 는 종료 기한이 지났을 때의 강제 중단용이며, 취소되면 채널에 남은 이벤트를 두고 바로 반환합니다.
+// Sink 는 여러 Worker 가 공유하므로 Close 는 호출하는 쪽(main)에서 담당합니다.
 func (w *Worker) Run(ctx context.Context) {
-	writer := &kafka.Writer{
-		Addr:     kafka.TCP(w.kafkaAddr),
-		Topic:    w.topic,
-		Balancer: &kafka.Hash{},
-		// 수동 배칭 대신 라이브러리 설정을 활용
-		BatchSize:    1000,
-		BatchTimeout: 50 * time.Millisecond,
-		RequiredAcks: kafka.RequireOne,
-		Async:        true, // 비동기 모드이므로 WriteMessages는 논블로킹
-		Compression:  kafka.Snappy,
-	}
-	defer writer.Close()
+	batch := make([]*event.Event, 0, maxBatch)
 
 	for {
 		select {
@@ -54,31 +50,42 @@ func (w *Worker) Run(ctx context.Context) {
 			return
 		case ev, ok := <-w.eventCh:
 			if !ok {
+				if err := w.sink.Flush(ctx); err != nil {
+					w.metrics.IncError("sink_flush")
+				}
 				return
 			}
 
-			// 1. JSON 직렬화
-			msgBytes, err := json.Marshal(ev)
-			if err != nil {
-				w.metrics.IncError("marshal_event")
-				continue
+			// 1. 채널에 이미 쌓여 있는 이벤트를 블로킹 없이 추가로 꺼내 배치 구성
+			batch = append(batch[:0], ev)
+		fill:
+			for len(batch) < maxBatch {
+				select {
+				case next, ok := <-w.eventCh:
+					if !ok {
+						break fill
+					}
+					batch = append(batch, next)
+				default:
+					break fill
+				}
 			}
 
-			// 2. 즉시 쓰기 (Async 모드라 내부 버퍼로 바로 들어감)
-			err = writer.WriteMessages(ctx, kafka.Message{
-				Key:   []byte(ev.UserID),
-				Value: msgBytes,
-			})
-
-			if err != nil {
-				w.metrics.IncError("write_messages")
-			} else {
-				// 3. 성공 시 메트릭 업데이트
-				w.metrics.IncEvent(ev.EventType)
+			// 2. Sink 로 전송
+			if err := w.sink.Write(ctx, batch); err != nil {
+				// Sink 에 들어가지 못한 이벤트는 전송 실패로 기록
+				w.metrics.IncError("sink_write")
+				w.metrics.IncDeliveryFailed(w.sink.Name(), -1, len(batch))
+				continue
 			}
 
-			// TIP: 2만 TPS 이상을 원하신다면 여기서 한 번에 여러 개를
-			// 꺼내는 for 루프를 추가할 수 있지만, Async 모드에선 이정도로도 충분합니다.
+			// 3. 성공 시 메트릭 업데이트
+			for _, e := range batch {
+				w.metrics.IncEvent(e.EventType)
+			}
+			if w.recordDelivery {
+				w.metrics.IncDelivered(w.sink.Name(), 0, len(batch))
+			}
 		}
 	}
 }
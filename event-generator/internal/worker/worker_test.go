@@ -0,0 +1,107 @@
+package worker_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"event-generator/internal/clock"
+	"event-generator/internal/event"
+	"event-generator/internal/fsm"
+	"event-generator/internal/generator"
+	"event-generator/internal/metrics"
+	"event-generator/internal/queue"
+	"event-generator/internal/rng"
+	"event-generator/internal/sink"
+	"event-generator/internal/user"
+	"event-generator/internal/worker"
+)
+
+// TestSessionManagerToMemorySink : 브로커 없이 SessionManager → EventQueue → Worker → MemorySink 전체 경로를 실행하고
+// 생성한 이벤트가 빠짐없이 순서대로 Sink 에 기록되는지 확인합니다.
+func TestSessionManagerToMemorySink(t *testing.T) {
+	const steps = 5000
+
+	m := metrics.NewInMemory()
+	q, err := queue.New(queue.Options{Capacity: 64, Policy: queue.PolicyBlock, Metrics: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clk := clock.NewVirtual(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	catalog := generator.DefaultCatalog()
+	rngs := rng.NewFactory(1)
+	up := user.NewUserPool(rngs.Source("user_pool"), user.DefaultProfileDistribution(), catalog.CountryNames(), user.DefaultPersonas(), clk)
+	up.EnsureUsers(200)
+	sm := user.NewSessionManager(up, fsm.NewSimpleFSM(nil), generator.NewPayloadGenerator(catalog), q, m, 30*time.Minute, 4, clk, rngs)
+
+	out := sink.NewMemorySink()
+	var wg sync.WaitGroup
+	for i := range 2 {
+		w := worker.NewWorker(i, q.C(), m, out)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Run(context.Background())
+		}()
+	}
+
+	for range steps {
+		clk.Advance(100 * time.Millisecond)
+		sm.Step()
+	}
+	if _, err := q.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	generated := sm.Generated()
+	// 모든 유저가 세션 중이면 Step 이 이벤트를 내보내지 않을 수 있으므로 Step 수와 비교하지 않음
+	if generated == 0 {
+		t.Fatalf("generated no events from %d steps", steps)
+	}
+	if got := int64(out.Len()); got != generated {
+		t.Fatalf("memory sink has %d events, SessionManager generated %d", got, generated)
+	}
+	snap := m.Snapshot()
+	if snap.Delivered != generated || snap.TotalEvents != generated {
+		t.Fatalf("metrics delivered=%d total=%d, want %d", snap.Delivered, snap.TotalEvents, generated)
+	}
+	if !out.Closed() {
+		t.Fatal("memory sink was not closed")
+	}
+
+	// 세션마다 session_start 가 하나이고 세션의 가장 이른 시각에 있어야 함
+	// (Worker 가 여러 개라 Sink 에 기록되는 순서는 섞일 수 있으므로 시각으로 확인)
+	bySession := make(map[string][]*event.Event)
+	for _, ev := range out.Events() {
+		if ev.SchemaVersion != event.SchemaVersion {
+			t.Fatalf("event %s has schema_version %d, want %d", ev.EventID, ev.SchemaVersion, event.SchemaVersion)
+		}
+		bySession[ev.SessionID] = append(bySession[ev.SessionID], ev)
+	}
+	for sid, evs := range bySession {
+		var starts int
+		var startTs int64
+		minTs := evs[0].EventTs
+		for _, ev := range evs {
+			minTs = min(minTs, ev.EventTs)
+			if ev.EventType == event.TypeSessionStart {
+				starts++
+				startTs = ev.EventTs
+				// 진입 화면은 첫 전이 전 상태 (새 세션은 홈 화면 browsing) 의 화면
+				if ev.Attributes.State != string(fsm.StateBrowsing) || ev.Attributes.Session == nil || ev.Attributes.Session.EntryPage != "home" {
+					t.Errorf("session %s: %s state=%q session=%+v, want state %q with entry page home",
+						sid, event.TypeSessionStart, ev.Attributes.State, ev.Attributes.Session, fsm.StateBrowsing)
+				}
+			}
+		}
+		if starts != 1 || startTs != minTs {
+			t.Errorf("session %s: %d %s events (ts %d), want one at the earliest ts %d", sid, starts, event.TypeSessionStart, startTs, minTs)
+		}
+	}
+}
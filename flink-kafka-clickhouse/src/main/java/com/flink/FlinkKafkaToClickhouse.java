@@ -42,6 +42,8 @@ public class FlinkKafkaToClickhouse {
         env.setRestartStrategy(RestartStrategies.fixedDelayRestart(3, 10000)); // 최대 3회 재시도, 10초 대기
 
         // 2. Kafka Source 설정 (OffsetsInitializer 추가 권장)
+        // 값은 JSON 문자열로만 읽습니다. event-generator 는 이 토픽에 avro/protobuf 직렬화를 허용하지 않습니다
+        // (config.PipelineTopic). 바이너리 포맷을 받으려면 여기에 Confluent wire format 디시리얼라이저를 먼저 추가해야 합니다.
         KafkaSource<String> kafkaSource = KafkaSource.<String>builder()
                 .setBootstrapServers("kafka:29092")
                 .setTopics("user_events")
@@ -72,7 +74,7 @@ public class FlinkKafkaToClickhouse {
         // 4. Sink 설정 (ClickHouse에 데이터 삽입)
         stream.addSink(
                 JdbcSink.sink(
-                        "INSERT INTO user_events_raw (event_id, user_id, session_id, event_type, event_ts, state, payload) VALUES (?, ?, ?, ?, ?, ?, ?)",
+                        "INSERT INTO user_events_raw (event_id, user_id, session_id, event_type, event_ts, state, page, device, referrer, product_id, vendor, price, currency, cart_lines, cart_quantity, home_country, loyalty_tier, session_number, end_reason, session_duration_ms, session_event_count, payload) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
                         (ps, value) -> {
                             try {
                                 JsonNode json = MAPPER.readTree(value);
@@ -81,8 +83,35 @@ public class FlinkKafkaToClickhouse {
                                 ps.setString(3, json.path("session_id").asText());
                                 ps.setString(4, json.path("event_type").asText());
                                 ps.setLong(5, json.path("event_ts").asLong());
-                                ps.setString(6, json.path("attributes").path("state").asText());
-                                ps.setString(7, value); // payload 전체 JSON 저장
+                                JsonNode attrs = json.path("attributes");
+                                JsonNode product = attrs.path("product");
+                                ps.setString(6, attrs.path("state").asText());
+                                ps.setString(7, attrs.path("page").asText());
+                                ps.setString(8, attrs.path("device").asText());
+                                ps.setString(9, attrs.path("referrer").asText());
+                                ps.setString(10, product.path("product_id").asText());
+                                ps.setString(11, product.path("vendor_type").asText());
+                                ps.setLong(12, product.path("price").asLong());
+                                ps.setString(13, product.path("currency").asText()); // price 의 통화
+                                // 장바구니 / 주문 상품 목록 (items) 요약
+                                JsonNode items = attrs.path("items");
+                                int cartQuantity = 0;
+                                for (JsonNode item : items) {
+                                    cartQuantity += item.path("quantity").asInt();
+                                }
+                                ps.setInt(14, items.size());
+                                ps.setLong(15, cartQuantity);
+                                // 유저 프로필 (재방문 / 코호트 분석)
+                                JsonNode user = attrs.path("user");
+                                ps.setString(16, user.path("home_country").asText());
+                                ps.setString(17, user.path("loyalty_tier").asText());
+                                ps.setLong(18, user.path("session_number").asLong());
+                                // session_start / session_end 에만 채워짐
+                                JsonNode session = attrs.path("session");
+                                ps.setString(19, session.path("end_reason").asText());
+                                ps.setLong(20, session.path("duration_ms").asLong());
+                                ps.setInt(21, session.path("event_count").asInt());
+                                ps.setString(22, value); // payload 전체 JSON 저장
                             } catch (Exception e) {
                                 // 에러 로깅 시 로깅 프레임워크 사용 권장
                                 System.err.println("JSON Parsing Error: " + value);